@@ -0,0 +1,155 @@
+// Package apperror adalah model error API: setiap error punya status HTTP,
+// kode mesin yang stabil dan pesan untuk manusia dari katalog i18n. Handler
+// cukup memanggil c.Error(apperror.X) lalu return; middleware.ErrorHandler
+// yang menulis response-nya dalam bahasa request.
+package apperror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+)
+
+type Error struct {
+	Status int
+	// Code stabil, dipakai client untuk bercabang; jangan diubah
+	Code string
+	// Key pesan di katalog i18n; kosong berarti sama dengan Code
+	Key string
+	// Message pesan yang sudah jadi (sudah dalam bahasa request), menggantikan Key
+	Message string
+	// Params mengisi placeholder {nama} di pesan
+	Params map[string]string
+	// Fields pesan per field untuk error validasi
+	Fields map[string]string
+	// Field kalau diisi, pesan error ini juga ditampilkan sebagai pesan field tsb
+	Field string
+	// Err penyebab asli; hanya dicatat di log, tidak pernah dikirim ke client
+	Err error
+}
+
+func define(status int, code string) *Error {
+	return &Error{Status: status, Code: code}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is menyamakan error berdasarkan Code, jadi errors.Is(err, apperror.NotFound)
+// tetap benar setelah With/Wrap
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Text pesan dalam bahasa lang dengan placeholder yang sudah diisi
+func (e *Error) Text(lang string) string {
+	if e.Message != "" {
+		return e.Message
+	}
+	key := e.Key
+	if key == "" {
+		key = e.Code
+	}
+	return i18n.T(lang, key, e.Params)
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	c.Params = copyMap(e.Params)
+	c.Fields = copyMap(e.Fields)
+	return &c
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// With mengisi placeholder {key} di pesan
+func (e *Error) With(key, value string) *Error {
+	c := e.clone()
+	if c.Params == nil {
+		c.Params = map[string]string{}
+	}
+	c.Params[key] = value
+	return c
+}
+
+// WithFields melampirkan pesan per field
+func (e *Error) WithFields(fields map[string]string) *Error {
+	c := e.clone()
+	c.Fields = fields
+	return c
+}
+
+// OnField menandai field yang menyebabkan error
+func (e *Error) OnField(name string) *Error {
+	c := e.clone()
+	c.Field = name
+	return c
+}
+
+// FieldMessages gabungan Fields dan pesan untuk Field
+func (e *Error) FieldMessages(lang string) map[string]string {
+	if e.Field == "" {
+		return e.Fields
+	}
+	out := copyMap(e.Fields)
+	if out == nil {
+		out = map[string]string{}
+	}
+	out[e.Field] = e.Text(lang)
+	return out
+}
+
+// WithKey memakai pesan lain dari katalog tanpa mengubah Code, mis. untuk
+// jenis pelanggaran kebijakan password
+func (e *Error) WithKey(key string) *Error {
+	c := e.clone()
+	c.Key = key
+	return c
+}
+
+// WithMessage mengganti pesan dengan teks yang sudah diterjemahkan, mis.
+// pesan validasi per field
+func (e *Error) WithMessage(msg string) *Error {
+	c := e.clone()
+	c.Message = msg
+	return c
+}
+
+// Wrap menyimpan penyebab untuk log
+func (e *Error) Wrap(err error) *Error {
+	c := e.clone()
+	c.Err = err
+	return c
+}
+
+// From mengubah error apa pun menjadi *Error; yang belum bertipe dianggap
+// error server supaya pesan mentah (mis. dari GORM) tidak bocor ke client
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal.Wrap(err)
+}
+
+// Server true untuk status 5xx, yang penyebabnya perlu dicatat
+func (e *Error) Server() bool {
+	return e.Status >= http.StatusInternalServerError
+}
@@ -0,0 +1,105 @@
+package apperror
+
+import "net/http"
+
+// Daftar error API. Code bagian dari kontrak dengan aplikasi mobile/web,
+// jadi hanya boleh ditambah, tidak diganti namanya. Pesannya ada di katalog
+// i18n dengan key yang sama dengan code.
+
+// Request tidak valid
+var (
+	InvalidRequest    = define(http.StatusBadRequest, "invalid_request")
+	ValidationFailed  = define(http.StatusBadRequest, "validation_failed")
+	InvalidParameter  = define(http.StatusBadRequest, "invalid_parameter")
+	MissingParameter  = define(http.StatusBadRequest, "missing_parameter")
+	InvalidDate       = define(http.StatusBadRequest, "invalid_date")
+	UnsupportedFormat = define(http.StatusBadRequest, "unsupported_format")
+	InvalidSort       = define(http.StatusBadRequest, "invalid_sort")
+	InvalidSortOrder  = define(http.StatusBadRequest, "invalid_sort_order")
+	WeakPassword      = define(http.StatusBadRequest, "weak_password")
+	PasswordReused    = define(http.StatusBadRequest, "password_reused")
+	ResetChannel      = define(http.StatusBadRequest, "reset_channel_invalid")
+	ResetCredentials  = define(http.StatusBadRequest, "reset_credentials_missing")
+	ResetTokenInvalid = define(http.StatusBadRequest, "reset_token_invalid")
+	UserNoEmail       = define(http.StatusBadRequest, "user_no_email")
+	PhoneMissing      = define(http.StatusBadRequest, "phone_missing")
+	KurirRequired     = define(http.StatusBadRequest, "kurir_required")
+	UnknownRole       = define(http.StatusBadRequest, "unknown_role")
+	UnknownPermission = define(http.StatusBadRequest, "unknown_permission")
+	OwnRoleChange     = define(http.StatusBadRequest, "own_role_change")
+	UnknownChannel    = define(http.StatusBadRequest, "unknown_channel")
+	DocumentMissing   = define(http.StatusBadRequest, "document_missing")
+	DocumentInvalid   = define(http.StatusBadRequest, "document_invalid")
+	DocumentExpired   = define(http.StatusBadRequest, "document_expired")
+)
+
+// Autentikasi
+var (
+	TokenMissing       = define(http.StatusUnauthorized, "token_missing")
+	TokenMalformed     = define(http.StatusUnauthorized, "token_malformed")
+	TokenInvalid       = define(http.StatusUnauthorized, "token_invalid")
+	SessionInvalid     = define(http.StatusUnauthorized, "session_invalid")
+	RefreshTokenReused = define(http.StatusUnauthorized, "refresh_token_reused")
+	InvalidCredentials = define(http.StatusUnauthorized, "invalid_credentials")
+	AccountInactive    = define(http.StatusUnauthorized, "account_inactive")
+	AccountPending     = define(http.StatusUnauthorized, "account_pending")
+	WrongPassword      = define(http.StatusUnauthorized, "wrong_password")
+	OTPInvalid         = define(http.StatusUnauthorized, "otp_invalid")
+	OTPExhausted       = define(http.StatusUnauthorized, "otp_exhausted")
+	ProxyUnauthorized  = define(http.StatusUnauthorized, "proxy_unauthorized")
+)
+
+// Akses ditolak
+var (
+	Forbidden         = define(http.StatusForbidden, "forbidden")
+	PermissionDenied  = define(http.StatusForbidden, "permission_denied")
+	OrderForbidden    = define(http.StatusForbidden, "order_forbidden")
+	ChatForbidden     = define(http.StatusForbidden, "chat_forbidden")
+	KurirForbidden    = define(http.StatusForbidden, "kurir_forbidden")
+	LocationForbidden = define(http.StatusForbidden, "location_forbidden")
+	ChannelForbidden  = define(http.StatusForbidden, "channel_forbidden")
+	TokenNotOwn       = define(http.StatusForbidden, "token_not_own")
+	RoleEscalation    = define(http.StatusForbidden, "role_escalation")
+	KurirInactive     = define(http.StatusForbidden, "kurir_inactive")
+)
+
+// Tidak ditemukan
+var (
+	RouteNotFound       = define(http.StatusNotFound, "route_not_found")
+	UserNotFound        = define(http.StatusNotFound, "user_not_found")
+	OrderNotFound       = define(http.StatusNotFound, "order_not_found")
+	KurirNotFound       = define(http.StatusNotFound, "kurir_not_found")
+	LocationNotFound    = define(http.StatusNotFound, "location_not_found")
+	SessionNotFound     = define(http.StatusNotFound, "session_not_found")
+	LoginLockNotFound   = define(http.StatusNotFound, "login_lock_not_found")
+	ApplicationNotFound = define(http.StatusNotFound, "application_not_found")
+	DocumentNotFound    = define(http.StatusNotFound, "document_not_found")
+	RoleNotFound        = define(http.StatusNotFound, "role_not_found")
+	ChannelNotFound     = define(http.StatusNotFound, "channel_not_found")
+)
+
+// Konflik dengan data yang ada
+var (
+	EmailTaken          = define(http.StatusConflict, "email_taken")
+	PhoneTaken          = define(http.StatusConflict, "phone_taken")
+	RoleExists          = define(http.StatusConflict, "role_exists")
+	RoleBuiltIn         = define(http.StatusConflict, "role_built_in")
+	RoleInUse           = define(http.StatusConflict, "role_in_use")
+	ApplicationReviewed = define(http.StatusConflict, "application_reviewed")
+)
+
+// Pembatasan
+var (
+	TooManyRequests   = define(http.StatusTooManyRequests, "too_many_requests")
+	LoginLocked       = define(http.StatusTooManyRequests, "login_locked")
+	ResetRecentlySent = define(http.StatusTooManyRequests, "reset_recently_sent")
+	OTPTooSoon        = define(http.StatusTooManyRequests, "otp_too_soon")
+	OTPTooMany        = define(http.StatusTooManyRequests, "otp_too_many")
+)
+
+// Server
+var (
+	Internal       = define(http.StatusInternalServerError, "internal_error")
+	RealtimeFailed = define(http.StatusBadGateway, "realtime_failed")
+	Unavailable    = define(http.StatusServiceUnavailable, "service_unavailable")
+)
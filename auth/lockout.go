@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ScopeIdentifier = "identifier"
+	ScopeIP         = "ip"
+)
+
+type throttleRule struct {
+	delayAfter  int           // mulai jeda progresif setelah sekian kegagalan
+	maxFailures int           // kunci setelah sekian kegagalan dalam window
+	window      time.Duration // kegagalan lebih lama dari ini tidak dihitung
+	baseLock    time.Duration // durasi kunci pertama, lalu berlipat dua
+	maxLock     time.Duration
+}
+
+// IP diberi batas lebih longgar karena satu IP bisa dipakai banyak user (NAT)
+var throttleRules = map[string]throttleRule{
+	ScopeIdentifier: {delayAfter: 3, maxFailures: 5, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 24 * time.Hour},
+	ScopeIP:         {delayAfter: 10, maxFailures: 30, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 2 * time.Hour},
+}
+
+const (
+	maxLoginDelay = time.Minute
+	// Riwayat kunci dilupakan kalau tidak ada kegagalan selama ini
+	lockoutMemory = 24 * time.Hour
+)
+
+// LockedError dikembalikan saat login harus ditunda
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e LockedError) Error() string {
+	return fmt.Sprintf("terlalu banyak percobaan login, coba lagi dalam %s", humanDuration(e.RetryAfter))
+}
+
+// LoginIdentifier menyeragamkan identifier login supaya "Budi@Mail.com"
+// dan "+62812..." dihitung sama dengan bentuk bakunya
+func LoginIdentifier(identifier string) string {
+	identifier = strings.TrimSpace(identifier)
+	if strings.Contains(identifier, "@") {
+		return strings.ToLower(identifier)
+	}
+	return utils.NormalizePhone(identifier)
+}
+
+// CheckLogin menolak percobaan login kalau identifier atau IP sedang
+// terkunci atau masih dalam jeda progresif. Error selain LockedError berarti
+// tabel throttle tidak bisa dibaca; pemanggil boleh meloloskan login (fail
+// open) tapi wajib mencatatnya.
+func CheckLogin(identifier, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+	var dbErr error
+
+	for scope, value := range throttleKeys(identifier, ip) {
+		var t model.LoginThrottle
+		err := config.DB.Where("scope = ? AND value = ?", scope, value).First(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			dbErr = fmt.Errorf("baca throttle %s: %w", scope, err)
+			continue
+		}
+		if d := throttleWait(t, throttleRules[scope], now); d > wait {
+			wait = d
+		}
+	}
+
+	if wait > 0 {
+		return LockedError{RetryAfter: wait}
+	}
+	return dbErr
+}
+
+// RecordLoginFailure mencatat kegagalan untuk identifier dan IP
+func RecordLoginFailure(identifier, ip string) error {
+	for scope, value := range throttleKeys(identifier, ip) {
+		if err := recordFailure(scope, value, time.Now()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordLoginSuccess menghapus catatan kegagalan identifier. Catatan IP
+// sengaja tidak dihapus supaya penyerang tidak bisa me-reset hitungan
+// dengan login ke akunnya sendiri.
+func RecordLoginSuccess(identifier string) error {
+	return config.DB.
+		Where("scope = ? AND value = ?", ScopeIdentifier, LoginIdentifier(identifier)).
+		Delete(&model.LoginThrottle{}).Error
+}
+
+// LockedLogins mengembalikan identifier/IP yang sedang terkunci
+func LockedLogins() ([]model.LoginThrottle, error) {
+	var locks []model.LoginThrottle
+	err := config.DB.Where("locked_until > ?", time.Now()).Order("locked_until DESC").Find(&locks).Error
+	return locks, err
+}
+
+// UnlockLogin membuka satu kunci (identifier atau IP) berdasarkan ID
+func UnlockLogin(id uint) error {
+	res := config.DB.Delete(&model.LoginThrottle{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UnlockUser membuka kunci login untuk email dan nomor HP user
+func UnlockUser(user model.User) error {
+	values := []string{}
+	for _, v := range []string{user.Email, user.Phone} {
+		if v != "" {
+			values = append(values, LoginIdentifier(v))
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return config.DB.
+		Where("scope = ? AND value IN ?", ScopeIdentifier, values).
+		Delete(&model.LoginThrottle{}).Error
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// CompareDummyPassword dipakai saat identifier tidak ditemukan supaya waktu
+// respon sama dengan salah password (mencegah enumerasi lewat timing)
+func CompareDummyPassword(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password-timing"), utils.BcryptCost())
+	})
+	bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+}
+
+func throttleKeys(identifier, ip string) map[string]string {
+	keys := map[string]string{}
+	if id := LoginIdentifier(identifier); id != "" {
+		keys[ScopeIdentifier] = id
+	}
+	if ip != "" {
+		keys[ScopeIP] = ip
+	}
+	return keys
+}
+
+func throttleWait(t model.LoginThrottle, rule throttleRule, now time.Time) time.Duration {
+	if t.LockedUntil != nil && t.LockedUntil.After(now) {
+		return t.LockedUntil.Sub(now)
+	}
+
+	if t.Failures < rule.delayAfter || now.Sub(t.LastFailureAt) > rule.window {
+		return 0
+	}
+
+	// Jeda 1, 2, 4, 8 ... detik sejak kegagalan terakhir
+	delay := time.Second << uint(t.Failures-rule.delayAfter)
+	if delay > maxLoginDelay || delay <= 0 {
+		delay = maxLoginDelay
+	}
+	if wait := t.LastFailureAt.Add(delay).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+func recordFailure(scope, value string, now time.Time) error {
+	rule := throttleRules[scope]
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var t model.LoginThrottle
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("scope = ? AND value = ?", scope, value).
+			First(&t).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			t = model.LoginThrottle{Scope: scope, Value: value}
+		} else if err != nil {
+			return err
+		}
+
+		applyFailure(&t, rule, now)
+
+		if t.ID == 0 {
+			// Dua request bersamaan bisa sama-sama membuat baris baru
+			return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&t).Error
+		}
+		return tx.Save(&t).Error
+	})
+}
+
+// applyFailure menambah satu kegagalan dan mengunci kalau batas tercapai.
+// Durasi kunci berlipat dua setiap terkunci lagi, sampai maxLock.
+func applyFailure(t *model.LoginThrottle, rule throttleRule, now time.Time) {
+	if now.Sub(t.LastFailureAt) > lockoutMemory {
+		t.Lockouts = 0
+	}
+	if now.Sub(t.LastFailureAt) > rule.window {
+		t.Failures = 0
+	}
+
+	t.Failures++
+	t.LastFailureAt = now
+
+	if t.Failures >= rule.maxFailures {
+		lock := rule.baseLock << uint(t.Lockouts)
+		if lock > rule.maxLock || lock <= 0 {
+			lock = rule.maxLock
+		}
+		until := now.Add(lock)
+		t.LockedUntil = &until
+		t.Lockouts++
+		t.Failures = 0
+	}
+}
+
+func humanDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d detik", int(d.Seconds()+0.5))
+	}
+	return fmt.Sprintf("%d menit", int(d.Minutes()+0.5))
+}
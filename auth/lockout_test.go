@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+var start = time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+// fail mencatat n kegagalan berjarak gap mulai dari at
+func fail(t *model.LoginThrottle, rule throttleRule, at time.Time, n int, gap time.Duration) time.Time {
+	for i := 0; i < n; i++ {
+		applyFailure(t, rule, at)
+		at = at.Add(gap)
+	}
+	return at.Add(-gap)
+}
+
+func TestLoginThrottleThresholds(t *testing.T) {
+	rule := throttleRules[ScopeIdentifier]
+
+	cases := []struct {
+		failures int
+		wantWait time.Duration // jeda tepat setelah kegagalan terakhir
+		locked   bool
+	}{
+		{1, 0, false},
+		{2, 0, false},
+		{3, time.Second, false},
+		{4, 2 * time.Second, false},
+		{5, 15 * time.Minute, true},
+	}
+
+	for _, tc := range cases {
+		var th model.LoginThrottle
+		last := fail(&th, rule, start, tc.failures, 0)
+
+		if got := throttleWait(th, rule, last); got != tc.wantWait {
+			t.Errorf("%d failures: wait %v, want %v", tc.failures, got, tc.wantWait)
+		}
+		if locked := th.LockedUntil != nil; locked != tc.locked {
+			t.Errorf("%d failures: locked %v, want %v", tc.failures, locked, tc.locked)
+		}
+	}
+}
+
+func TestLoginThrottleIPIsLooser(t *testing.T) {
+	rule := throttleRules[ScopeIP]
+	var th model.LoginThrottle
+
+	fail(&th, rule, start, rule.maxFailures-1, 0)
+	if th.LockedUntil != nil {
+		t.Fatalf("IP locked after %d failures, want lock at %d", rule.maxFailures-1, rule.maxFailures)
+	}
+	applyFailure(&th, rule, start)
+	if th.LockedUntil == nil || !th.LockedUntil.Equal(start.Add(rule.baseLock)) {
+		t.Fatalf("IP lock until %v, want %v", th.LockedUntil, start.Add(rule.baseLock))
+	}
+}
+
+func TestLoginThrottleExpiry(t *testing.T) {
+	rule := throttleRules[ScopeIdentifier]
+
+	t.Run("progressive delay passes", func(t *testing.T) {
+		var th model.LoginThrottle
+		last := fail(&th, rule, start, 3, 0)
+		if throttleWait(th, rule, last.Add(time.Second)) != 0 {
+			t.Fatal("still delayed after the delay passed")
+		}
+	})
+
+	t.Run("lock expires", func(t *testing.T) {
+		var th model.LoginThrottle
+		last := fail(&th, rule, start, rule.maxFailures, 0)
+		if throttleWait(th, rule, last.Add(rule.baseLock-time.Second)) == 0 {
+			t.Fatal("not locked before lock expiry")
+		}
+		if wait := throttleWait(th, rule, last.Add(rule.baseLock)); wait != 0 {
+			t.Fatalf("still locked after expiry: %v", wait)
+		}
+	})
+
+	t.Run("failures outside window are forgotten", func(t *testing.T) {
+		var th model.LoginThrottle
+		last := fail(&th, rule, start, rule.maxFailures-1, 0)
+		applyFailure(&th, rule, last.Add(rule.window+time.Second))
+		if th.Failures != 1 || th.LockedUntil != nil {
+			t.Fatalf("failures %d locked %v, want a fresh count", th.Failures, th.LockedUntil)
+		}
+	})
+
+	t.Run("stale delay is ignored", func(t *testing.T) {
+		var th model.LoginThrottle
+		last := fail(&th, rule, start, 4, 0)
+		if wait := throttleWait(th, rule, last.Add(rule.window+time.Second)); wait != 0 {
+			t.Fatalf("wait %v after window, want 0", wait)
+		}
+	})
+}
+
+func TestLoginThrottleRepeatedLockouts(t *testing.T) {
+	rule := throttleRules[ScopeIdentifier]
+	var th model.LoginThrottle
+	at := start
+
+	// Kunci berlipat dua setiap kali, dibatasi maxLock
+	for i, want := range []time.Duration{15 * time.Minute, 30 * time.Minute, time.Hour, 2 * time.Hour} {
+		last := fail(&th, rule, at, rule.maxFailures, 0)
+		if got := th.LockedUntil.Sub(last); got != want {
+			t.Fatalf("lockout %d: %v, want %v", i+1, got, want)
+		}
+		at = *th.LockedUntil
+	}
+
+	var lock time.Duration
+	for i := 0; i < 10; i++ {
+		last := fail(&th, rule, at, rule.maxFailures, 0)
+		lock = th.LockedUntil.Sub(last)
+		if lock > rule.maxLock {
+			t.Fatalf("lock %v exceeds max %v", lock, rule.maxLock)
+		}
+		at = *th.LockedUntil
+	}
+	if lock != rule.maxLock {
+		t.Fatalf("lock %v, want capped at %v", lock, rule.maxLock)
+	}
+
+	// Setelah lama tidak gagal, durasi kunci kembali ke awal
+	last := fail(&th, rule, at.Add(lockoutMemory+time.Minute), rule.maxFailures, 0)
+	if got := th.LockedUntil.Sub(last); got != rule.baseLock {
+		t.Fatalf("lock after quiet period %v, want %v", got, rule.baseLock)
+	}
+}
+
+func TestLoginIdentifier(t *testing.T) {
+	cases := map[string]string{
+		" Budi@Mail.COM ":  "budi@mail.com",
+		"+62 812-3456-789": "08123456789",
+		"628123456789":     "08123456789",
+		"08123456789":      "08123456789",
+	}
+	for in, want := range cases {
+		if got := LoginIdentifier(in); got != want {
+			t.Errorf("LoginIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
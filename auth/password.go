@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Jumlah password terakhir (termasuk yang sekarang) yang tidak boleh dipakai lagi
+const passwordHistorySize = 5
+
+var (
+	ErrWrongPassword  = errors.New("password lama salah")
+	ErrPasswordReused = errors.New("password baru tidak boleh sama dengan 5 password terakhir")
+)
+
+// PolicyError membungkus pelanggaran kebijakan password supaya
+// controller bisa membedakannya dari error server
+type PolicyError struct{ Err error }
+
+func (e PolicyError) Error() string { return e.Err.Error() }
+func (e PolicyError) Unwrap() error { return e.Err }
+
+// ChangePassword mengganti password user yang sedang login. Session lain
+// dicabut, session yang dipakai untuk mengganti (keepSessionID) tetap aktif.
+func ChangePassword(userID, keepSessionID uint, oldPassword, newPassword string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)) != nil {
+			return ErrWrongPassword
+		}
+
+		return setPassword(tx, userID, newPassword, keepSessionID)
+	})
+}
+
+// setPassword memeriksa kebijakan & riwayat, menyimpan hash baru,
+// menghanguskan token reset yang tersisa, lalu mencabut session user
+// (kecuali keepSessionID, 0 = cabut semua)
+func setPassword(tx *gorm.DB, userID uint, newPassword string, keepSessionID uint) error {
+	var user model.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		return err
+	}
+
+	if err := utils.ValidatePassword(newPassword, user.Name, user.Email); err != nil {
+		return PolicyError{err}
+	}
+
+	var history []model.PasswordHistory
+	if err := tx.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(passwordHistorySize - 1).
+		Find(&history).Error; err != nil {
+		return err
+	}
+
+	previous := []string{user.Password}
+	for _, h := range history {
+		previous = append(previous, h.PasswordHash)
+	}
+	for _, hash := range previous {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return PolicyError{ErrPasswordReused}
+		}
+	}
+
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if user.Password != "" {
+		if err := tx.Create(&model.PasswordHistory{UserID: userID, PasswordHash: user.Password}).Error; err != nil {
+			return err
+		}
+		// Riwayat yang lebih lama dari batas tidak perlu disimpan
+		if err := tx.Where("user_id = ? AND id NOT IN (?)", userID,
+			tx.Model(&model.PasswordHistory{}).Select("id").Where("user_id = ?", userID).
+				Order("created_at DESC").Limit(passwordHistorySize-1),
+		).Delete(&model.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("password", hashed).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(&model.PasswordReset{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error; err != nil {
+		return err
+	}
+
+	sessions := tx.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if keepSessionID != 0 {
+		sessions = sessions.Where("id <> ?", keepSessionID)
+	}
+	return sessions.Update("revoked_at", now).Error
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	resetTokenTTL      = 30 * time.Minute
+	resetResendMinimum = time.Minute
+)
+
+var (
+	ErrResetInvalid = errors.New("token reset tidak valid atau sudah kedaluwarsa")
+	ErrResetTooSoon = errors.New("permintaan reset terlalu sering")
+)
+
+// CreatePasswordReset membuat token reset sekali pakai untuk user.
+// requestedBy diisi ID admin kalau reset dipicu admin.
+func CreatePasswordReset(userID uint, requestedBy *uint) (string, error) {
+	var last model.PasswordReset
+	err := config.DB.Where("user_id = ?", userID).Order("created_at DESC").First(&last).Error
+	if err == nil && time.Since(last.CreatedAt) < resetResendMinimum {
+		return "", ErrResetTooSoon
+	}
+
+	token, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	reset := model.PasswordReset{
+		UserID:      userID,
+		TokenHash:   utils.HashToken(token),
+		RequestedBy: requestedBy,
+		ExpiresAt:   time.Now().Add(resetTokenTTL),
+	}
+	if err := config.DB.Create(&reset).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ResetPasswordWithToken memakai token reset lalu mengganti password
+func ResetPasswordWithToken(token, newPassword string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		var reset model.PasswordReset
+		err := tx.Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", utils.HashToken(token), time.Now()).
+			First(&reset).Error
+		if err != nil {
+			return ErrResetInvalid
+		}
+
+		res := tx.Model(&model.PasswordReset{}).
+			Where("id = ? AND used_at IS NULL", reset.ID).
+			Update("used_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrResetInvalid
+		}
+
+		return setPassword(tx, reset.UserID, newPassword, 0)
+	})
+}
+
+// ResetPassword mengganti password user yang sudah terverifikasi lewat
+// jalur lain (mis. OTP). Aturan password tetap diterapkan.
+func ResetPassword(userID uint, newPassword string) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		return setPassword(tx, userID, newPassword, 0)
+	})
+}
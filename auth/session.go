@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionInvalid = errors.New("session tidak valid atau sudah berakhir")
+	ErrRefreshReused  = errors.New("refresh token sudah pernah dipakai")
+	ErrUserInactive   = errors.New("akun tidak aktif")
+)
+
+// TokenPair adalah pasangan token yang dikirim saat login / refresh
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // detik, umur access token
+}
+
+// last_seen_at tidak ditulis di setiap request, cukup sekali per interval ini
+const lastSeenInterval = time.Minute
+
+// Device adalah info perangkat yang dicatat saat login
+type Device struct {
+	Name      string
+	Platform  string
+	IP        string
+	UserAgent string
+}
+
+// StartSession membuat session baru untuk user lalu menerbitkan token
+func StartSession(user model.User, device Device) (TokenPair, error) {
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	session := model.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: utils.HashToken(refreshToken),
+		DeviceName:       truncate(device.Name, 100),
+		Platform:         truncate(device.Platform, 30),
+		IP:               truncate(device.IP, 45),
+		UserAgent:        truncate(device.UserAgent, 255),
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(utils.RefreshTokenTTL),
+	}
+	if err := config.DB.Create(&session).Error; err != nil {
+		return TokenPair{}, err
+	}
+
+	return issue(user, session.ID, refreshToken)
+}
+
+// RefreshSession menukar refresh token dengan pasangan token baru (rotasi).
+// Refresh token lama yang dipakai lagi dianggap bocor: session langsung dicabut.
+func RefreshSession(refreshToken string, ip string) (TokenPair, model.User, error) {
+	hash := utils.HashToken(refreshToken)
+
+	var session model.Session
+	err := config.DB.Preload("User").Where("refresh_token_hash = ?", hash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		var reused model.Session
+		if config.DB.Where("previous_token_hash = ? AND revoked_at IS NULL", hash).First(&reused).Error == nil {
+			_ = RevokeSession(reused.ID)
+			return TokenPair{}, model.User{}, ErrRefreshReused
+		}
+		return TokenPair{}, model.User{}, ErrSessionInvalid
+	}
+	if err != nil {
+		return TokenPair{}, model.User{}, err
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return TokenPair{}, model.User{}, ErrSessionInvalid
+	}
+	if session.User.StatusKerja != "aktif" {
+		return TokenPair{}, model.User{}, ErrUserInactive
+	}
+
+	newToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return TokenPair{}, model.User{}, err
+	}
+
+	// Update bersyarat supaya dua refresh bersamaan tidak sama-sama berhasil
+	res := config.DB.Model(&model.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, hash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  utils.HashToken(newToken),
+			"previous_token_hash": hash,
+			"expires_at":          time.Now().Add(utils.RefreshTokenTTL),
+			"last_seen_at":        time.Now(),
+			"ip":                  truncate(ip, 45),
+		})
+	if res.Error != nil {
+		return TokenPair{}, model.User{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return TokenPair{}, model.User{}, ErrSessionInvalid
+	}
+
+	pair, err := issue(session.User, session.ID, newToken)
+	return pair, session.User, err
+}
+
+// ValidateSession memastikan session di access token masih aktif dan
+// pemiliknya belum dinonaktifkan. Dipanggil di setiap request terproteksi,
+// sekalian mencatat last seen device.
+func ValidateSession(claims *utils.JWTClaims, ip string) error {
+	if claims.SessionID == 0 {
+		return ErrSessionInvalid
+	}
+
+	// Hanya session yang memang tidak ada dianggap tidak valid; gangguan
+	// database diteruskan supaya user tidak ikut ter-logout
+	var session model.Session
+	err := config.DB.Preload("User").First(&session, claims.SessionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrSessionInvalid
+	}
+	if err != nil {
+		return fmt.Errorf("baca session %d: %w", claims.SessionID, err)
+	}
+
+	if session.UserID != claims.UserID || session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return ErrSessionInvalid
+	}
+	if session.User.StatusKerja != "aktif" {
+		return ErrUserInactive
+	}
+
+	if time.Since(session.LastSeenAt) > lastSeenInterval {
+		config.DB.Model(&model.Session{}).Where("id = ?", session.ID).
+			Updates(map[string]interface{}{"last_seen_at": time.Now(), "ip": truncate(ip, 45)})
+	}
+	return nil
+}
+
+// ActiveSessions mengembalikan device yang masih login, terbaru dulu
+func ActiveSessions(userID uint) ([]model.Session, error) {
+	var sessions []model.Session
+	err := config.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeUserSession mencabut satu session milik user tertentu.
+// Mengembalikan ErrSessionInvalid kalau session bukan milik user itu.
+func RevokeUserSession(userID, sessionID uint) error {
+	res := config.DB.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrSessionInvalid
+	}
+	return nil
+}
+
+// RevokeSession mencabut satu session (logout satu device)
+func RevokeSession(sessionID uint) error {
+	return config.DB.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions mencabut semua session user kecuali exceptSessionID (0 = semua)
+func RevokeUserSessions(userID uint, exceptSessionID uint) error {
+	q := config.DB.Model(&model.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptSessionID != 0 {
+		q = q.Where("id <> ?", exceptSessionID)
+	}
+	return q.Update("revoked_at", time.Now()).Error
+}
+
+func issue(user model.User, sessionID uint, refreshToken string) (TokenPair, error) {
+	accessToken, err := utils.GenerateToken(user.ID, user.Role, sessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package centrifugo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+)
+
+// Namespace channel yang dipakai aplikasi:
+//
+//	chat:<order_id>     — chat customer ↔ kurir
+//	tracking:<order_id> — lokasi kurir selama pesanan diantar
+const (
+	NamespaceChat     = "chat"
+	NamespaceTracking = "tracking"
+)
+
+const (
+	ConnectionTokenTTL   = 24 * time.Hour
+	SubscriptionTokenTTL = time.Hour
+)
+
+var (
+	ErrNoSecret        = errors.New("CENTRIFUGO_SECRET belum diatur")
+	ErrUnknownChannel  = errors.New("channel tidak dikenal")
+	ErrChannelNotFound = errors.New("pesanan untuk channel ini tidak ditemukan")
+	ErrForbidden       = errors.New("tidak punya akses ke channel ini")
+)
+
+// ChatChannel mengembalikan nama channel chat sebuah pesanan
+func ChatChannel(orderID uint) string {
+	return NamespaceChat + ":" + strconv.FormatUint(uint64(orderID), 10)
+}
+
+// ConnectionToken membuat token koneksi untuk user
+func ConnectionToken(userID uint) (string, error) {
+	return sign(jwt.MapClaims{
+		"sub": strconv.FormatUint(uint64(userID), 10),
+		"exp": time.Now().Add(ConnectionTokenTTL).Unix(),
+	})
+}
+
+// SubscriptionToken membuat token untuk satu channel. Centrifugo menolak
+// subscribe ke channel private tanpa token yang cocok dengan sub & channel.
+func SubscriptionToken(userID uint, channel string) (string, error) {
+	return sign(jwt.MapClaims{
+		"sub":     strconv.FormatUint(uint64(userID), 10),
+		"channel": channel,
+		"exp":     time.Now().Add(SubscriptionTokenTTL).Unix(),
+	})
+}
+
+func sign(claims jwt.MapClaims) (string, error) {
+	if settings.Secret == "" {
+		return "", ErrNoSecret
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(settings.Secret))
+}
+
+// Authorize mengecek apakah actor boleh subscribe ke channel:
+// chat hanya untuk peserta pesanan, tracking untuk yang boleh melihat pesanan
+func Authorize(actor policy.Actor, channel string) error {
+	namespace, id, ok := strings.Cut(channel, ":")
+	if !ok {
+		return ErrUnknownChannel
+	}
+	orderID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return ErrUnknownChannel
+	}
+
+	var allow func(policy.Actor, model.Order) bool
+	switch namespace {
+	case NamespaceChat:
+		allow = policy.Chat
+	case NamespaceTracking:
+		allow = policy.ViewOrder
+	default:
+		return ErrUnknownChannel
+	}
+
+	var order model.Order
+	if err := config.DB.First(&order, orderID).Error; err != nil {
+		return ErrChannelNotFound
+	}
+	if !allow(actor, order) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package centrifugo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+)
+
+var (
+	settings config.CentrifugoConfig
+	client   = &http.Client{Timeout: 5 * time.Second}
+)
+
+// Configure dipanggil saat startup dengan konfigurasi Centrifugo
+func Configure(cfg config.CentrifugoConfig) {
+	settings = cfg
+}
+
+// ProxySecret adalah header rahasia yang dikirim Centrifugo ke proxy endpoint
+func ProxySecret() string {
+	return settings.ProxySecret
+}
+
+// PublishError berarti Centrifugo menjawab selain 200
+type PublishError struct {
+	Status int
+	Body   string
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("centrifugo publish gagal (%d): %s", e.Status, e.Body)
+}
+
+// Publish mengirim data ke channel lewat server API Centrifugo
+func Publish(ctx context.Context, channel string, data interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"method": "publish",
+		"params": map[string]interface{}{"channel": channel, "data": data},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", settings.APIKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &PublishError{Status: resp.StatusCode, Body: string(respBody)}
+	}
+	return nil
+}
+
+// Ping mengecek server API Centrifugo bisa dihubungi. Jawaban HTTP apa pun
+// di bawah 500 dianggap hidup (endpoint publish menolak GET, itu wajar).
+func Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, settings.APIURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("centrifugo menjawab %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -1,47 +1,90 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
+	"time"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func LoadEnv() {
-	err := godotenv.Load()
+// Jeda maksimum antar percobaan koneksi
+const maxConnectBackoff = 30 * time.Second
+
+// ConnectDB membuka koneksi dengan beberapa kali percobaan. Kalau semua
+// gagal, error dikembalikan dan server tidak boleh jalan tanpa database.
+func ConnectDB(cfg DBConfig) error {
+	backoff := cfg.ConnectBackoff
+
+	var err error
+	for attempt := 1; attempt <= cfg.ConnectAttempts; attempt++ {
+		if DB, err = open(cfg); err == nil {
+			break
+		}
+
+		log.Printf("⚠️ Gagal koneksi ke database (percobaan %d/%d): %v", attempt, cfg.ConnectAttempts, err)
+		if attempt < cfg.ConnectAttempts {
+			time.Sleep(backoff)
+			backoff = min(backoff*2, maxConnectBackoff)
+		}
+	}
 	if err != nil {
-		log.Println("Tidak bisa load .env file, pastikan file .env tersedia")
+		DB = nil
+		return fmt.Errorf("database tidak bisa dihubungi setelah %d percobaan: %w", cfg.ConnectAttempts, err)
 	}
+
+	log.Println("✅ Berhasil terkoneksi ke database PostgreSQL")
+	return nil
 }
 
-func ConnectDB() {
-	LoadEnv()
+func open(cfg DBConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
 
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=require",
-		host, port, user, password, dbname,
-	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
 
-	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	// if err != nil {
-	// 	log.Fatal("Gagal koneksi ke database:", err)
-	// }
+// PingDB dipakai readiness check
+func PingDB(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("database belum terhubung")
+	}
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Println("⚠️ Gagal koneksi ke database:", err)
-		return // jangan fatal, biarin server tetep hidup
+		return err
 	}
+	return sqlDB.PingContext(ctx)
+}
 
-	log.Println("✅ Berhasil terkoneksi ke database PostgreSQL")
+// CloseDB menutup pool koneksi saat server berhenti
+func CloseDB() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
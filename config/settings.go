@@ -0,0 +1,326 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Config adalah seluruh pengaturan aplikasi. Urutan sumber: nilai default,
+// file YAML (opsional, CONFIG_FILE atau ./config.yaml), lalu environment
+// (termasuk .env) yang selalu menang.
+type Config struct {
+	Env        string           `yaml:"env"` // development, production
+	Server     ServerConfig     `yaml:"server"`
+	DB         DBConfig         `yaml:"db"`
+	JWT        utils.KeyConfig  `yaml:"jwt"`
+	Centrifugo CentrifugoConfig `yaml:"centrifugo"`
+	Password   PasswordConfig   `yaml:"password"`
+	OTPSecret  string           `yaml:"otp_secret"`
+	UploadDir  string           `yaml:"upload_dir"`
+	ResetURL   string           `yaml:"reset_password_url"`
+	RateLimit  string           `yaml:"rate_limit_store"` // memory, postgres
+}
+
+type ServerConfig struct {
+	Port string `yaml:"port"`
+
+	// Origin browser yang boleh memanggil API. Default kosong: tidak ada
+	// origin lain yang diizinkan sampai diisi eksplisit.
+	CORSOrigins []string `yaml:"cors_origins"`
+
+	// IP/CIDR reverse proxy yang X-Forwarded-For-nya dipercaya. Kosong berarti
+	// tidak ada proxy dipercaya dan IP klien diambil dari koneksi langsung.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`  // termasuk upload dokumen kurir
+	WriteTimeout      time.Duration `yaml:"write_timeout"` // termasuk export csv/xlsx
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+}
+
+type DBConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	// Percobaan koneksi saat start, jeda berlipat dua mulai ConnectBackoff
+	ConnectAttempts int           `yaml:"connect_attempts"`
+	ConnectBackoff  time.Duration `yaml:"connect_backoff"`
+
+	// Terapkan migrasi yang tertunda saat server start; matikan kalau
+	// migrasi dijalankan terpisah lewat `misi-paket migrate up`
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+// DSN untuk driver postgres
+func (d DBConfig) DSN() string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		d.Host, d.Port, d.User, d.Password, d.Name, d.SSLMode)
+}
+
+type CentrifugoConfig struct {
+	APIURL      string `yaml:"api_url"`
+	APIKey      string `yaml:"api_key"`
+	Secret      string `yaml:"secret"`
+	ProxySecret string `yaml:"proxy_secret"`
+}
+
+type PasswordConfig struct {
+	MinLength  int `yaml:"min_length"`
+	BcryptCost int `yaml:"bcrypt_cost"`
+}
+
+var current = defaults()
+
+// Get mengembalikan konfigurasi yang sudah dimuat lewat Load
+func Get() *Config {
+	return current
+}
+
+func defaults() *Config {
+	return &Config{
+		Env: "production",
+		Server: ServerConfig{
+			Port:              "8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      2 * time.Minute,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		DB: DBConfig{
+			Port:            "5432",
+			SSLMode:         "require",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnectAttempts: 5,
+			ConnectBackoff:  time.Second,
+			AutoMigrate:     true,
+		},
+		Password:  PasswordConfig{MinLength: 8, BcryptCost: 10},
+		UploadDir: "uploads",
+		ResetURL:  "faiexpress://reset-password",
+		RateLimit: "memory",
+	}
+}
+
+// Load membaca .env, YAML dan environment lalu memvalidasinya.
+// Dipanggil sekali saat start; error berarti server tidak boleh jalan.
+func Load() (*Config, error) {
+	LoadEnv()
+
+	cfg := defaults()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		path = "config.yaml"
+	}
+	if raw, err := os.ReadFile(path); err == nil {
+		if err := yaml.Unmarshal(raw, cfg); err != nil {
+			return nil, fmt.Errorf("gagal membaca %s: %w", path, err)
+		}
+	} else if os.Getenv("CONFIG_FILE") != "" {
+		return nil, fmt.Errorf("CONFIG_FILE %s tidak bisa dibaca: %w", path, err)
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	current = cfg
+	return cfg, nil
+}
+
+func applyEnv(cfg *Config) error {
+	str := map[string]*string{
+		"APP_ENV":                 &cfg.Env,
+		"PORT":                    &cfg.Server.Port,
+		"DB_HOST":                 &cfg.DB.Host,
+		"DB_PORT":                 &cfg.DB.Port,
+		"DB_USER":                 &cfg.DB.User,
+		"DB_PASSWORD":             &cfg.DB.Password,
+		"DB_NAME":                 &cfg.DB.Name,
+		"DB_SSLMODE":              &cfg.DB.SSLMode,
+		"JWT_ALG":                 &cfg.JWT.Algorithm,
+		"JWT_KID":                 &cfg.JWT.KeyID,
+		"JWT_SECRET":              &cfg.JWT.Secret,
+		"JWT_PRIVATE_KEY_FILE":    &cfg.JWT.PrivateKeyFile,
+		"JWT_OLD_SECRETS":         &cfg.JWT.OldSecrets,
+		"JWT_OLD_PUBLIC_KEYS":     &cfg.JWT.OldPublicKeys,
+		"CENTRIFUGO_API_URL":      &cfg.Centrifugo.APIURL,
+		"CENTRIFUGO_API_KEY":      &cfg.Centrifugo.APIKey,
+		"CENTRIFUGO_SECRET":       &cfg.Centrifugo.Secret,
+		"CENTRIFUGO_PROXY_SECRET": &cfg.Centrifugo.ProxySecret,
+		"OTP_SECRET":              &cfg.OTPSecret,
+		"UPLOAD_DIR":              &cfg.UploadDir,
+		"RESET_PASSWORD_URL":      &cfg.ResetURL,
+		"RATE_LIMIT_STORE":        &cfg.RateLimit,
+	}
+	for name, field := range str {
+		if v, ok := os.LookupEnv(name); ok {
+			*field = strings.TrimSpace(v)
+		}
+	}
+
+	num := map[string]*int{
+		"PASSWORD_MIN_LENGTH": &cfg.Password.MinLength,
+		"BCRYPT_COST":         &cfg.Password.BcryptCost,
+		"DB_MAX_OPEN_CONNS":   &cfg.DB.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":   &cfg.DB.MaxIdleConns,
+		"DB_CONNECT_ATTEMPTS": &cfg.DB.ConnectAttempts,
+	}
+	for name, field := range num {
+		v, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(v) == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%s harus angka: %q", name, v)
+		}
+		*field = n
+	}
+
+	durations := map[string]*time.Duration{
+		"SERVER_READ_HEADER_TIMEOUT": &cfg.Server.ReadHeaderTimeout,
+		"SERVER_READ_TIMEOUT":        &cfg.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":       &cfg.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        &cfg.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":           &cfg.Server.ShutdownTimeout,
+		"DB_CONN_MAX_LIFETIME":       &cfg.DB.ConnMaxLifetime,
+		"DB_CONN_MAX_IDLE_TIME":      &cfg.DB.ConnMaxIdleTime,
+		"DB_CONNECT_BACKOFF":         &cfg.DB.ConnectBackoff,
+	}
+	for name, field := range durations {
+		v, ok := os.LookupEnv(name)
+		if !ok || strings.TrimSpace(v) == "" {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%s harus durasi, mis. 30s atau 5m: %q", name, v)
+		}
+		*field = d
+	}
+
+	if v, ok := os.LookupEnv("DB_AUTO_MIGRATE"); ok && strings.TrimSpace(v) != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("DB_AUTO_MIGRATE harus true/false: %q", v)
+		}
+		cfg.DB.AutoMigrate = b
+	}
+
+	if v := os.Getenv("CORS_ORIGINS"); v != "" {
+		cfg.Server.CORSOrigins = splitList(v)
+	}
+	if v, ok := os.LookupEnv("TRUSTED_PROXIES"); ok {
+		cfg.Server.TrustedProxies = splitList(v)
+	}
+
+	// OTP memakai secret JWT kalau tidak diatur sendiri
+	if cfg.OTPSecret == "" {
+		cfg.OTPSecret = cfg.JWT.Secret
+	}
+	return nil
+}
+
+// splitList memecah daftar dipisah koma dan membuang item kosong
+func splitList(v string) []string {
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// Validate mengumpulkan semua kesalahan konfigurasi sekaligus
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	if _, err := strconv.Atoi(c.Server.Port); err != nil {
+		errs = append(errs, fmt.Errorf("PORT harus angka: %q", c.Server.Port))
+	}
+	check(c.Server.ReadHeaderTimeout > 0 && c.Server.ReadTimeout > 0 && c.Server.WriteTimeout > 0 && c.Server.IdleTimeout > 0,
+		"SERVER_*_TIMEOUT harus lebih dari 0")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT harus lebih dari 0")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "TRUSTED_PROXIES berisi IP/CIDR tidak valid: %q", proxy)
+	}
+
+	check(c.DB.Host != "", "DB_HOST wajib diisi")
+	check(c.DB.User != "", "DB_USER wajib diisi")
+	check(c.DB.Name != "", "DB_NAME wajib diisi")
+	if _, err := strconv.Atoi(c.DB.Port); err != nil {
+		errs = append(errs, fmt.Errorf("DB_PORT harus angka: %q", c.DB.Port))
+	}
+	check(c.DB.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS harus lebih dari 0")
+	check(c.DB.MaxIdleConns >= 0 && c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "DB_MAX_IDLE_CONNS harus 0..DB_MAX_OPEN_CONNS")
+	check(c.DB.ConnMaxLifetime >= 0 && c.DB.ConnMaxIdleTime >= 0, "DB_CONN_MAX_LIFETIME/IDLE_TIME tidak boleh negatif")
+	check(c.DB.ConnectAttempts >= 1, "DB_CONNECT_ATTEMPTS minimal 1")
+	check(c.DB.ConnectBackoff > 0, "DB_CONNECT_BACKOFF harus lebih dari 0")
+	switch c.DB.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		errs = append(errs, fmt.Errorf("DB_SSLMODE tidak dikenal: %q", c.DB.SSLMode))
+	}
+
+	if _, err := utils.NewKeySet(c.JWT); err != nil {
+		errs = append(errs, err)
+	}
+	if err := utils.CheckDevSecret(c.JWT, c.Env); err != nil {
+		errs = append(errs, err)
+	}
+
+	check(c.Centrifugo.APIURL != "", "CENTRIFUGO_API_URL wajib diisi")
+	check(c.Centrifugo.APIKey != "", "CENTRIFUGO_API_KEY wajib diisi")
+	check(c.Centrifugo.Secret != "", "CENTRIFUGO_SECRET wajib diisi")
+	check(c.Centrifugo.ProxySecret != "", "CENTRIFUGO_PROXY_SECRET wajib diisi")
+	check(c.OTPSecret != "", "OTP_SECRET wajib diisi kalau JWT_ALG bukan HS256")
+
+	check(c.Password.MinLength >= 8 && c.Password.MinLength <= 72, "PASSWORD_MIN_LENGTH harus 8..72")
+	check(c.Password.BcryptCost >= 10 && c.Password.BcryptCost <= 14, "BCRYPT_COST harus 10..14")
+	check(c.RateLimit == "memory" || c.RateLimit == "postgres", "RATE_LIMIT_STORE harus memory atau postgres")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("konfigurasi tidak valid:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// LoadEnv memuat .env kalau ada; di production variabel biasanya sudah
+// diset langsung sehingga file ini boleh tidak ada
+func LoadEnv() {
+	_ = godotenv.Load()
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSecret = "secret-acak-untuk-test-0123456789abcdef"
+
+// validConfig konfigurasi production minimal yang lolos Validate
+func validConfig() *Config {
+	cfg := defaults()
+	cfg.DB.Host, cfg.DB.User, cfg.DB.Name = "localhost", "postgres", "misi_paket"
+	cfg.JWT.Algorithm, cfg.JWT.KeyID, cfg.JWT.Secret = "HS256", "test", testSecret
+	cfg.OTPSecret = testSecret
+	cfg.Centrifugo = CentrifugoConfig{
+		APIURL:      "http://localhost:9000/api/publish",
+		APIKey:      "api-key",
+		Secret:      testSecret,
+		ProxySecret: "proxy-secret",
+	}
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   []string // potongan pesan error; kosong berarti valid
+	}{
+		{"valid", func(*Config) {}, nil},
+		{"missing centrifugo secrets", func(c *Config) {
+			c.Centrifugo.Secret, c.Centrifugo.ProxySecret, c.Centrifugo.APIKey = "", "", ""
+		}, []string{"CENTRIFUGO_SECRET", "CENTRIFUGO_PROXY_SECRET", "CENTRIFUGO_API_KEY"}},
+		{"short jwt secret", func(c *Config) { c.JWT.Secret = "pendek" }, []string{"JWT_SECRET minimal"}},
+		{"missing jwt secret", func(c *Config) { c.JWT.Secret = "" }, []string{"JWT_SECRET wajib"}},
+		{"dev secret in production", func(c *Config) {
+			c.JWT.Secret = "rahasiafai-dev-jwt-secret-ganti-di-production"
+		}, []string{"development tidak boleh"}},
+		{"dev secret in development", func(c *Config) {
+			c.Env = "development"
+			c.JWT.Secret = "rahasiafai-dev-jwt-secret-ganti-di-production"
+		}, nil},
+		{"missing otp secret", func(c *Config) { c.OTPSecret = "" }, []string{"OTP_SECRET"}},
+		{"invalid trusted proxy", func(c *Config) { c.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy"} }, []string{`"proxy"`}},
+		{"all errors at once", func(c *Config) {
+			c.DB.Host, c.Server.Port = "", "delapan"
+		}, []string{"DB_HOST", "PORT harus angka"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validConfig()
+			tc.mutate(cfg)
+			err := cfg.Validate()
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("no error, want %v", tc.want)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not mention %q:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// Tanpa CORS_ORIGINS tidak ada origin yang diizinkan, dan itu tetap valid
+func TestCORSDefaultsToEmpty(t *testing.T) {
+	cfg := validConfig()
+	if len(cfg.Server.CORSOrigins) != 0 {
+		t.Fatalf("default CORS origins %v, want none", cfg.Server.CORSOrigins)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOTPSecretFallsBackToJWTSecret(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+
+	t.Run("unset", func(t *testing.T) {
+		t.Setenv("OTP_SECRET", "")
+		cfg := defaults()
+		if err := applyEnv(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.OTPSecret != testSecret {
+			t.Fatalf("OTPSecret %q, want the JWT secret", cfg.OTPSecret)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		t.Setenv("OTP_SECRET", "secret-otp-sendiri")
+		cfg := defaults()
+		if err := applyEnv(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if cfg.OTPSecret != "secret-otp-sendiri" {
+			t.Fatalf("OTPSecret %q, want OTP_SECRET", cfg.OTPSecret)
+		}
+	})
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" https://a.example , ,https://b.example,")
+	if strings.Join(got, "|") != "https://a.example|https://b.example" {
+		t.Fatalf("splitList = %q", got)
+	}
+	if splitList(" , ") != nil {
+		t.Fatal("blank list should be empty")
+	}
+}
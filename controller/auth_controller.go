@@ -1,55 +1,133 @@
 package controller
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
 	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
 	"github.com/mubarok-ridho/misi-paket.backend/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// controller/login.go
+// AuthHandler login dan pendaftaran mandiri
+type AuthHandler struct {
+	users *service.UserService
+}
+
+func NewAuthHandler(users *service.UserService) *AuthHandler {
+	return &AuthHandler{users: users}
+}
 
-func Login(c *gin.Context) {
+func (h *AuthHandler) Login(c *gin.Context) {
 	var input struct {
 		Identifier string `json:"email"` // bisa email atau no_telp
 		Password   string `json:"password"`
+		DeviceName string `json:"device_name"` // opsional, mis. "Samsung A14"
+		Platform   string `json:"platform"`    // opsional: android, ios, web
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak valid"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	var user model.User
-	if err := config.DB.
-		Where("email = ? OR phone = ?", input.Identifier, input.Identifier).
-		First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Email atau nomor tidak ditemukan"})
+	// Dinormalkan dulu supaya "Budi@X.id " dan "budi@x.id" berbagi satu
+	// hitungan gagal login
+	identifier := strings.ToLower(strings.TrimSpace(input.Identifier))
+
+	// ClientIP hanya membaca X-Forwarded-For dari TRUSTED_PROXIES, jadi
+	// hitungan per IP tidak bisa di-reset dengan header palsu
+	ip := c.ClientIP()
+	var locked auth.LockedError
+	if err := auth.CheckLogin(identifier, ip); errors.As(err, &locked) {
+		respondLoginLocked(c, err)
+		return
+	} else if err != nil {
+		// Gangguan DB tidak boleh mematikan login, tapi harus terlihat di log
+		middleware.Logf(c, "⚠️ Gagal memeriksa throttle login: %v", err)
+	}
+
+	// Pesan sama untuk akun tidak ada dan password salah (anti enumerasi)
+	invalid := func() {
+		if err := auth.RecordLoginFailure(identifier, ip); err != nil {
+			middleware.Logf(c, "⚠️ Gagal mencatat login gagal: %v", err)
+		}
+		c.Error(apperror.InvalidCredentials)
+	}
+
+	user, err := h.users.FindForLogin(c.Request.Context(), identifier)
+	if errors.Is(err, service.ErrUserNotFound) {
+		auth.CompareDummyPassword(input.Password)
+		invalid()
+		return
+	}
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password salah"})
+		invalid()
+		return
+	}
+
+	if err := auth.RecordLoginSuccess(identifier); err != nil {
+		middleware.Logf(c, "⚠️ Gagal reset hitungan login: %v", err)
+	}
+
+	switch user.StatusKerja {
+	case "aktif":
+	case "pending":
+		c.Error(apperror.AccountPending)
+		return
+	default:
+		c.Error(apperror.AccountInactive)
 		return
 	}
 
-	if user.StatusKerja != "aktif" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Akun anda tidak aktif"})
+	respondLogin(c, user, input.DeviceName, input.Platform)
+}
+
+func respondLoginLocked(c *gin.Context, err error) {
+	var locked auth.LockedError
+	if !errors.As(err, &locked) {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	// Generate token dan kirim user info
-	token, err := utils.GenerateToken(user.ID, user.Role)
+	seconds := strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds())))
+	c.Header("Retry-After", seconds)
+	c.Error(apperror.LoginLocked.With("seconds", seconds))
+}
+
+// respondLogin membuat session baru dan mengirim token + user info.
+// Dipakai bersama oleh login password dan login OTP.
+func respondLogin(c *gin.Context, user model.User, deviceName, platform string) {
+	tokens, err := auth.StartSession(user, auth.Device{
+		Name:      deviceName,
+		Platform:  platform,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"token": token,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 		"user": gin.H{
 			"id":    user.ID,
 			"name":  user.Name,
@@ -59,66 +137,114 @@ func Login(c *gin.Context) {
 	})
 }
 
-func Register(c *gin.Context) {
-	var input model.User
+// RegisterRequest adalah satu-satunya data yang boleh dikirim saat daftar
+// mandiri. Role, status, dan status kerja selalu ditentukan server.
+type RegisterRequest struct {
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Email    string `json:"email" binding:"required,email,max=100"`
+	Phone    string `json:"phone" binding:"required,phone_id"`
+	Password string `json:"password" binding:"required"`
+}
 
-	// Bind JSON ke struct User
+// POST /register — hanya untuk customer
+func (h *AuthHandler) Register(c *gin.Context) {
+	var input RegisterRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Data tidak valid"})
+		c.Error(validationError(c, err))
 		return
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengamankan password"})
-		return
+	user := model.User{
+		Name:        strings.TrimSpace(input.Name),
+		Email:       strings.ToLower(strings.TrimSpace(input.Email)),
+		Phone:       utils.NormalizePhone(input.Phone),
+		Role:        "customer",
+		StatusKerja: "aktif",
 	}
-	input.Password = string(hashedPassword)
-
-	// Simpan ke DB
-	if err := config.DB.Create(&input).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mendaftar user"})
+	if err := h.users.Create(c.Request.Context(), &user, input.Password); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Registrasi berhasil", "user": input})
+	c.JSON(http.StatusCreated, gin.H{"message": i18n.Msg(c, "msg.register_success"), "user": dto.NewUserPublic(user)})
 }
 
-// controller/user_controller.go
-
-func ChangePassword(c *gin.Context) {
-	id := c.Param("id")
+// POST /auth/refresh
+func RefreshToken(c *gin.Context) {
 	var input struct {
-		OldPassword string `json:"old_password"`
-		NewPassword string `json:"new_password"`
+		RefreshToken string `json:"refresh_token" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format input salah"})
+		c.Error(validationError(c, err))
 		return
 	}
 
-	var user model.User
-	if err := config.DB.First(&user, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	tokens, _, err := auth.RefreshSession(input.RefreshToken, c.ClientIP())
+	switch {
+	case errors.Is(err, auth.ErrRefreshReused):
+		c.Error(apperror.RefreshTokenReused)
+		return
+	case errors.Is(err, auth.ErrSessionInvalid), errors.Is(err, auth.ErrUserInactive):
+		c.Error(apperror.SessionInvalid)
+		return
+	case err != nil:
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	// Validasi password lama
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.OldPassword)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Password lama salah"})
+	c.JSON(http.StatusOK, tokens)
+}
+
+// POST /api/logout (session saat ini)
+func Logout(c *gin.Context) {
+	if err := auth.RevokeSession(c.GetUint("sessionID")); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	// Hash password baru
-	hashed, _ := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
-	user.Password = string(hashed)
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.logout_success")})
+}
+
+// POST /api/logout-all (semua device)
+func LogoutAll(c *gin.Context) {
+	if err := auth.RevokeUserSessions(c.GetUint("userID"), 0); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.logout_all_success")})
+}
 
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan password baru"})
+// PUT /api/password — ganti password user yang sedang login
+func ChangePassword(c *gin.Context) {
+	var input struct {
+		OldPassword string `json:"old_password" binding:"required"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.InvalidRequest)
+		return
+	}
+
+	err := auth.ChangePassword(c.GetUint("userID"), c.GetUint("sessionID"), input.OldPassword, input.NewPassword)
+
+	var policyErr auth.PolicyError
+	switch {
+	case errors.Is(err, auth.ErrWrongPassword):
+		c.Error(apperror.WrongPassword)
+		return
+	case errors.Is(err, auth.ErrPasswordReused):
+		c.Error(apperror.PasswordReused)
+		return
+	case errors.As(err, &policyErr):
+		c.Error(passwordError(policyErr.Err))
+		return
+	case err != nil:
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Password berhasil diubah"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.password_changed")})
 }
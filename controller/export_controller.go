@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gorm.io/gorm"
+)
+
+// Flush ke client tiap sekian baris supaya download langsung jalan
+const exportFlushEvery = 500
+
+// tableWriter dipakai bersama oleh export CSV dan XLSX
+type tableWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func (t *csvTableWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch val := v.(type) {
+		case nil:
+			record[i] = ""
+		case time.Time:
+			record[i] = val.Format("2006-01-02 15:04:05")
+		default:
+			record[i] = fmt.Sprint(val)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// newTableWriter membuat writer sesuai ?format= (csv atau xlsx). Status 200
+// dan header attachment baru dipasang setelah writer berhasil dibuat.
+func newTableWriter(c *gin.Context, name string) (tableWriter, error) {
+	filename := fmt.Sprintf("%s_%s", name, time.Now().Format("20060102_150405"))
+
+	var w tableWriter
+	var contentType, ext string
+	switch c.Query("format") {
+	case "", "csv":
+		w = &csvTableWriter{w: csv.NewWriter(c.Writer)}
+		contentType, ext = "text/csv; charset=utf-8", "csv"
+	case "xlsx":
+		xw, err := utils.NewXLSXWriter(c.Writer, name)
+		if err != nil {
+			return nil, fmt.Errorf("membuat file xlsx: %w", err)
+		}
+		w = xw
+		contentType, ext = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"
+	default:
+		return nil, apperror.UnsupportedFormat
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, ext))
+	c.Status(http.StatusOK)
+	return w, nil
+}
+
+// rowSource memberi baris export satu per satu; ok false kalau sudah habis
+type rowSource func() (values []interface{}, ok bool, err error)
+
+// writeExport menulis header tabel lalu semua baris dari next
+func writeExport(c *gin.Context, name string, header []interface{}, next rowSource) {
+	w, err := newTableWriter(c, name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := w.WriteRow(header); err != nil {
+		failExport(c, err)
+		return
+	}
+
+	for n := 1; ; n++ {
+		values, ok, err := next()
+		if err != nil {
+			failExport(c, err)
+			return
+		}
+		if !ok {
+			break
+		}
+		if err := w.WriteRow(values); err != nil {
+			failExport(c, err)
+			return
+		}
+		if n%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		failExport(c, err)
+	}
+}
+
+// failExport: selama belum ada byte terkirim, client masih bisa dijawab
+// error biasa. Setelah itu status 200 sudah lewat, jadi koneksi diputus
+// supaya client tahu file-nya terpotong, bukan menerima file rusak.
+func failExport(c *gin.Context, err error) {
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+	middleware.Logf(c, "⚠️ Export terhenti: %v", err)
+	c.Abort()
+	panic(http.ErrAbortHandler)
+}
+
+// exportOrderQuery adalah query dasar order + nama customer/kurir,
+// memakai filter yang sama dengan GetAllOrders
+func exportOrderQuery(c *gin.Context) (*gorm.DB, bool) {
+	f, ok := parseOrderFilter(c)
+	if !ok {
+		return nil, false
+	}
+
+	return f.Apply(repository.JoinOrderUsers(config.DB.Model(&model.Order{}))), true
+}
+
+type orderExportRow struct {
+	ID            uint
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	CustomerName  *string
+	KurirName     *string
+	Layanan       string
+	Status        string
+	Nominal       *uint
+	MetodeBayar   string
+	PaymentStatus *string
+}
+
+func (r orderExportRow) values() []interface{} {
+	var nominal, customer, kurir, paymentStatus interface{}
+	if r.Nominal != nil {
+		nominal = *r.Nominal
+	}
+	if r.CustomerName != nil {
+		customer = *r.CustomerName
+	}
+	if r.KurirName != nil {
+		kurir = *r.KurirName
+	}
+	if r.PaymentStatus != nil {
+		paymentStatus = *r.PaymentStatus
+	}
+	return []interface{}{
+		r.ID, r.CreatedAt, r.UpdatedAt, customer, kurir,
+		r.Layanan, r.Status, nominal, r.MetodeBayar, paymentStatus,
+	}
+}
+
+var orderExportHeader = []interface{}{
+	"ID", "Dibuat", "Diperbarui", "Customer", "Kurir",
+	"Layanan", "Status", "Nominal", "Metode Bayar", "Status Pembayaran",
+}
+
+const orderExportColumns = "orders.id, orders.created_at, orders.updated_at, " +
+	"customer.name AS customer_name, kurir.name AS kurir_name, orders.layanan, " +
+	"orders.status, orders.nominal, orders.metode_bayar, orders.payment_status"
+
+// streamExport menjalankan query baris per baris dan menulis ke tableWriter
+func streamExport[T any](c *gin.Context, name string, q *gorm.DB, header []interface{}, toValues func(T) []interface{}) {
+	if format := c.Query("format"); format != "" && format != "csv" && format != "xlsx" {
+		c.Error(apperror.UnsupportedFormat)
+		return
+	}
+
+	rows, err := q.Rows()
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+	defer rows.Close()
+
+	writeExport(c, name, header, func() ([]interface{}, bool, error) {
+		if !rows.Next() {
+			return nil, false, rows.Err()
+		}
+		var row T
+		if err := config.DB.ScanRows(rows, &row); err != nil {
+			return nil, false, err
+		}
+		return toValues(row), true, nil
+	})
+}
+
+// 🔸 Export Orders (GET /api/export/orders?format=csv|xlsx + filter yang sama dengan GetAllOrders)
+func ExportOrders(c *gin.Context) {
+	q, ok := exportOrderQuery(c)
+	if !ok {
+		return
+	}
+
+	q = q.Select(orderExportColumns).Order("orders.created_at ASC")
+	streamExport(c, "orders", q, orderExportHeader, orderExportRow.values)
+}
+
+// 🔸 Export Payments: hanya order yang sudah ditagih
+func ExportPayments(c *gin.Context) {
+	q, ok := exportOrderQuery(c)
+	if !ok {
+		return
+	}
+
+	q = q.Select(orderExportColumns).
+		Where("orders.nominal IS NOT NULL").
+		Order("orders.updated_at ASC")
+
+	header := []interface{}{"ID Order", "Tanggal Tagihan", "Customer", "Kurir", "Nominal", "Metode Bayar", "Status Pembayaran"}
+	streamExport(c, "payments", q, header, func(r orderExportRow) []interface{} {
+		v := r.values()
+		return []interface{}{v[0], v[2], v[3], v[4], v[7], v[8], v[9]}
+	})
+}
+
+type kurirPerformanceRow struct {
+	KurirID        uint
+	KurirName      string
+	TotalOrder     int64
+	OrderSelesai   int64
+	OrderProses    int64
+	PembayaranDone int64
+	Pendapatan     int64
+}
+
+// 🔸 Export Performa Kurir: rekap per kurir dalam rentang tanggal
+func ExportKurirPerformance(c *gin.Context) {
+	q, ok := exportOrderQuery(c)
+	if !ok {
+		return
+	}
+
+	q = q.Select(
+		"orders.kurir_id AS kurir_id, " +
+			"COALESCE(kurir.name, '') AS kurir_name, " +
+			"COUNT(*) AS total_order, " +
+			"COUNT(*) FILTER (WHERE orders.status = 'selesai') AS order_selesai, " +
+			"COUNT(*) FILTER (WHERE orders.status = 'proses') AS order_proses, " +
+			"COUNT(*) FILTER (WHERE orders.payment_status = 'done') AS pembayaran_done, " +
+			"COALESCE(SUM(orders.nominal) FILTER (WHERE orders.status = 'selesai'), 0) AS pendapatan",
+	).
+		Group("orders.kurir_id, kurir.name").
+		Order("pendapatan DESC")
+
+	header := []interface{}{"ID Kurir", "Nama Kurir", "Total Order", "Selesai", "Proses", "Pembayaran Lunas", "Pendapatan"}
+	streamExport(c, "kurir_performance", q, header, func(r kurirPerformanceRow) []interface{} {
+		return []interface{}{r.KurirID, r.KurirName, r.TotalOrder, r.OrderSelesai, r.OrderProses, r.PembayaranDone, r.Pendapatan}
+	})
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+)
+
+var exportHeader = []interface{}{"ID", "Dibuat", "Customer", "Nominal"}
+
+// sliceRows sumber baris dari slice; fail (kalau ada) dikembalikan setelah
+// semua baris habis
+func sliceRows(rows [][]interface{}, fail error) rowSource {
+	i := 0
+	return func() ([]interface{}, bool, error) {
+		if i == len(rows) {
+			return nil, false, fail
+		}
+		i++
+		return rows[i-1], true, nil
+	}
+}
+
+func serveExport(t *testing.T, query string, next rowSource) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/export", func(c *gin.Context) { writeExport(c, "orders", exportHeader, next) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export"+query, nil))
+	return w
+}
+
+// xlsxRows membuka workbook lalu mengambil teks setiap sel di sheet pertama
+func xlsxRows(t *testing.T, body []byte) [][]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	f, err := zr.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.NewDecoder(f).Decode(&sheet); err != nil {
+		t.Fatal(err)
+	}
+	var rows [][]string
+	for _, r := range sheet.Rows {
+		var cells []string
+		for _, c := range r.Cells {
+			cells = append(cells, c.Value+c.Inline)
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func TestExportXLSXContents(t *testing.T) {
+	created := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	w := serveExport(t, "?format=xlsx", sliceRows([][]interface{}{
+		{uint(1), created, "Budi & <Siti>", uint(15000)},
+		{uint(2), created, nil, nil},
+	}, nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Disposition"); !strings.HasSuffix(got, `.xlsx"`) {
+		t.Fatalf("Content-Disposition %q", got)
+	}
+
+	want := [][]string{
+		{"ID", "Dibuat", "Customer", "Nominal"},
+		{"1", "2025-03-01 09:30:00", "Budi & <Siti>", "15000"},
+		{"2", "2025-03-01 09:30:00", "", ""},
+	}
+	got := xlsxRows(t, w.Body.Bytes())
+	if len(got) != len(want) {
+		t.Fatalf("%d rows, want %d: %q", len(got), len(want), got)
+	}
+	for i := range want {
+		if strings.Join(got[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestExportCSVContents(t *testing.T) {
+	w := serveExport(t, "", sliceRows([][]interface{}{{uint(1), "2025-03-01", "Budi, Jr.", nil}}, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1][2] != "Budi, Jr." || records[1][3] != "" {
+		t.Fatalf("records %q", records)
+	}
+}
+
+// Gagal sebelum ada byte terkirim: client masih dapat error JSON biasa
+func TestExportFailsBeforeStreaming(t *testing.T) {
+	w := serveExport(t, "?format=xlsx", sliceRows(nil, errors.New("connection reset")))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d, want 500", w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "" {
+		t.Fatalf("Content-Disposition %q on an error response", got)
+	}
+	if strings.Contains(w.Body.String(), "connection reset") {
+		t.Fatalf("raw error leaked: %s", w.Body)
+	}
+}
+
+// Gagal di tengah stream: koneksi diputus lewat http.ErrAbortHandler,
+// bukan file yang terlihat lengkap padahal terpotong
+func TestExportAbortsMidStream(t *testing.T) {
+	rows := make([][]interface{}, 3000)
+	for i := range rows {
+		rows[i] = []interface{}{uint(i + 1), "2025-03-01", strings.Repeat("x", 20), uint(1000)}
+	}
+
+	for _, format := range []string{"csv", "xlsx"} {
+		t.Run(format, func(t *testing.T) {
+			defer func() {
+				if p := recover(); p != http.ErrAbortHandler {
+					t.Fatalf("recovered %v, want http.ErrAbortHandler", p)
+				}
+			}()
+			w := serveExport(t, "?format="+format, sliceRows(rows, io.ErrUnexpectedEOF))
+			t.Fatalf("export finished with status %d", w.Code)
+		})
+	}
+}
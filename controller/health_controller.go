@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+)
+
+// GET /healthz — liveness: proses masih hidup, tanpa cek dependency
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GET /readyz — readiness: database dan Centrifugo bisa dihubungi
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := gin.H{}
+	ready := true
+	for name, ping := range map[string]func(context.Context) error{
+		"database":   config.PingDB,
+		"centrifugo": centrifugo.Ping,
+	} {
+		if err := ping(ctx); err != nil {
+			// Detail error hanya di log; response publik cukup "down"
+			middleware.Logf(c, "⚠️ Readiness %s gagal: %v", name, err)
+			checks[name] = "down"
+			ready = false
+			continue
+		}
+		checks[name] = "ok"
+	}
+
+	status, text := http.StatusOK, "ready"
+	if !ready {
+		status, text = http.StatusServiceUnavailable, "not ready"
+	}
+	c.JSON(status, gin.H{"status": text, "checks": checks})
+}
@@ -1,65 +1,74 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
-	"sync"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
 )
 
-// In-memory store untuk menyimpan lokasi kurir
-var kurirLocations = struct {
-	sync.RWMutex
-	data map[uint]map[string]float64 // kurir_id -> {"lat": ..., "lng": ...}
-}{
-	data: make(map[uint]map[string]float64),
+// LocationHandler endpoint tracking lokasi kurir
+type LocationHandler struct {
+	locations *service.LocationService
 }
 
-// POST /kurir/track
-func UpdateKurirLocation(c *gin.Context) {
+func NewLocationHandler(locations *service.LocationService) *LocationHandler {
+	return &LocationHandler{locations: locations}
+}
+
+// POST /kurir/track — lokasi selalu dicatat untuk kurir yang login,
+// kurir_id dari body diabaikan
+func (h *LocationHandler) UpdateKurirLocation(c *gin.Context) {
 	var req struct {
-		KurirID uint    `json:"kurir_id"`
-		Lat     float64 `json:"lat"`
-		Lng     float64 `json:"lng"`
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format JSON tidak valid"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	kurirLocations.Lock()
-	kurirLocations.data[req.KurirID] = map[string]float64{
-		"lat": req.Lat,
-		"lng": req.Lng,
+	loc := repository.Location{Lat: req.Lat, Lng: req.Lng}
+	if err := h.locations.Track(c.Request.Context(), c.GetUint("userID"), loc); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
 	}
-	kurirLocations.Unlock()
 
-	c.JSON(http.StatusOK, gin.H{"message": "Lokasi kurir diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.kurir_location_updated")})
 }
 
 // GET /kurir/track/:id
-func GetKurirLocation(c *gin.Context) {
+func (h *LocationHandler) GetKurirLocation(c *gin.Context) {
 	var req struct {
 		KurirID uint `uri:"id" binding:"required"`
 	}
 
 	if err := c.ShouldBindUri(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID kurir tidak valid"})
+		c.Error(apperror.InvalidParameter.With("param", "id"))
 		return
 	}
 
-	kurirLocations.RLock()
-	loc, found := kurirLocations.data[req.KurirID]
-	kurirLocations.RUnlock()
-
-	if !found {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Lokasi belum tersedia"})
+	loc, err := h.locations.Locate(c.Request.Context(), policy.ActorFrom(c), req.KurirID)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		c.Error(apperror.LocationForbidden)
+		return
+	case errors.Is(err, service.ErrLocationNotFound):
+		c.Error(apperror.LocationNotFound)
+		return
+	case err != nil:
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"lat": loc["lat"],
-		"lng": loc["lng"],
+		"lat": loc.Lat,
+		"lng": loc.Lng,
 	})
 }
@@ -0,0 +1,317 @@
+package controller
+
+import (
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/notifier"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gorm.io/gorm"
+)
+
+const maxDocumentSize = 5 << 20 // 5 MB per berkas
+
+// Berkas wajib saat mendaftar kurir
+var kurirDocumentTypes = []string{"ktp", "sim", "stnk", "foto_kendaraan"}
+
+var allowedDocumentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// KurirApplyRequest adalah field teks form pendaftaran kurir (multipart)
+type KurirApplyRequest struct {
+	Name          string `form:"name" json:"name" binding:"required,min=2,max=100"`
+	Email         string `form:"email" json:"email" binding:"required,email,max=100"`
+	Phone         string `form:"phone" json:"phone" binding:"required,phone_id"`
+	Password      string `form:"password" json:"password" binding:"required"`
+	Kendaraan     string `form:"kendaraan" json:"kendaraan" binding:"required,max=50"`
+	PlatNomor     string `form:"plat_nomor" json:"plat_nomor" binding:"required,max=15"`
+	SIMExpiredAt  string `form:"sim_expired_at" json:"sim_expired_at" binding:"required"`   // YYYY-MM-DD
+	STNKExpiredAt string `form:"stnk_expired_at" json:"stnk_expired_at" binding:"required"` // YYYY-MM-DD
+}
+
+// uploadDir dari konfigurasi UPLOAD_DIR (default ./uploads)
+func uploadDir() string {
+	return config.Get().UploadDir
+}
+
+// POST /kurir/apply — calon kurir mengirim data diri + KTP, SIM, STNK, foto kendaraan
+func (h *AuthHandler) ApplyKurir(c *gin.Context) {
+	var input KurirApplyRequest
+	if err := c.ShouldBind(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	expiry := map[string]*time.Time{}
+	for docType, value := range map[string]string{"sim": input.SIMExpiredAt, "stnk": input.STNKExpiredAt} {
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			field := docType + "_expired_at"
+			c.Error(apperror.InvalidDate.With("param", field).OnField(field))
+			return
+		}
+		if !t.After(time.Now()) {
+			c.Error(apperror.DocumentExpired.With("document", strings.ToUpper(docType)).OnField(docType + "_expired_at"))
+			return
+		}
+		expiry[docType] = &t
+	}
+
+	files := map[string]*multipart.FileHeader{}
+	contentTypes := map[string]string{}
+	for _, docType := range kurirDocumentTypes {
+		fh, err := c.FormFile(docType)
+		if err != nil {
+			c.Error(apperror.DocumentMissing.With("document", docType).OnField(docType))
+			return
+		}
+		contentType, ok := checkDocument(fh)
+		if !ok {
+			c.Error(apperror.DocumentInvalid.With("document", docType).OnField(docType))
+			return
+		}
+		files[docType] = fh
+		contentTypes[docType] = contentType
+	}
+
+	kendaraan := strings.TrimSpace(input.Kendaraan)
+	platNomor := strings.ToUpper(strings.TrimSpace(input.PlatNomor))
+	user := model.User{
+		Name:        strings.TrimSpace(input.Name),
+		Email:       strings.ToLower(strings.TrimSpace(input.Email)),
+		Phone:       utils.NormalizePhone(input.Phone),
+		Role:        "kurir",
+		Kendaraan:   &kendaraan,
+		PlatNomor:   &platNomor,
+		Status:      "offline",
+		StatusKerja: "pending", // baru bisa login & online setelah disetujui admin
+	}
+
+	if err := h.users.CheckNewUser(c.Request.Context(), user, input.Password); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+	user.Password = hashedPassword
+
+	var application model.KurirApplication
+	var savedDir string
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+
+		savedDir = filepath.Join(uploadDir(), "kurir", strconv.FormatUint(uint64(user.ID), 10))
+		if err := os.MkdirAll(savedDir, 0o750); err != nil {
+			return err
+		}
+
+		for _, docType := range kurirDocumentTypes {
+			path := filepath.Join(savedDir, docType+allowedDocumentTypes[contentTypes[docType]])
+			if err := c.SaveUploadedFile(files[docType], path); err != nil {
+				return err
+			}
+			doc := model.KurirDocument{
+				UserID:      user.ID,
+				Type:        docType,
+				FilePath:    path,
+				ContentType: contentTypes[docType],
+				ExpiresAt:   expiry[docType],
+			}
+			if err := tx.Create(&doc).Error; err != nil {
+				return err
+			}
+		}
+
+		application = model.KurirApplication{UserID: user.ID, Status: "pending"}
+		return tx.Create(&application).Error
+	})
+	if err != nil {
+		if savedDir != "" {
+			os.RemoveAll(savedDir)
+		}
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":        i18n.Msg(c, "msg.kurir_application_sent"),
+		"application_id": application.ID,
+	})
+}
+
+// checkDocument memeriksa ukuran dan jenis berkas dari isi file, bukan dari nama
+func checkDocument(fh *multipart.FileHeader) (string, bool) {
+	if fh.Size == 0 || fh.Size > maxDocumentSize {
+		return "", false
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := f.Read(head)
+	contentType := http.DetectContentType(head[:n])
+	_, ok := allowedDocumentTypes[contentType]
+	return contentType, ok
+}
+
+// GET /api/kurir/applications?status=pending (admin)
+func GetKurirApplications(c *gin.Context) {
+	q := config.DB.Preload("User").Order("created_at ASC")
+	if status := c.Query("status"); status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var apps []model.KurirApplication
+	if err := q.Find(&apps).Error; err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewKurirApplicationViews(apps))
+}
+
+// GET /api/kurir/applications/:id (admin)
+func GetKurirApplication(c *gin.Context) {
+	var app model.KurirApplication
+	if err := config.DB.Preload("User").First(&app, c.Param("id")).Error; err != nil {
+		c.Error(apperror.ApplicationNotFound)
+		return
+	}
+
+	var docs []model.KurirDocument
+	if err := config.DB.Where("user_id = ?", app.UserID).Order("id ASC").Find(&docs).Error; err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewKurirApplicationView(app, docs))
+}
+
+// GET /api/kurir/documents/:id/file (admin)
+func GetKurirDocumentFile(c *gin.Context) {
+	var doc model.KurirDocument
+	if err := config.DB.First(&doc, c.Param("id")).Error; err != nil {
+		c.Error(apperror.DocumentNotFound)
+		return
+	}
+
+	c.Header("Content-Type", doc.ContentType)
+	c.File(doc.FilePath)
+}
+
+// PUT /api/kurir/applications/:id/approve (admin)
+func ApproveKurirApplication(c *gin.Context) {
+	reviewKurirApplication(c, "approved", nil)
+}
+
+// PUT /api/kurir/applications/:id/reject (admin) — alasan wajib diisi
+func RejectKurirApplication(c *gin.Context) {
+	var input struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	reviewKurirApplication(c, "rejected", &reason)
+}
+
+func reviewKurirApplication(c *gin.Context, status string, reason *string) {
+	var app model.KurirApplication
+	if err := config.DB.Preload("User").First(&app, c.Param("id")).Error; err != nil {
+		c.Error(apperror.ApplicationNotFound)
+		return
+	}
+
+	if app.Status != "pending" {
+		c.Error(apperror.ApplicationReviewed)
+		return
+	}
+
+	statusKerja := "aktif"
+	if status == "rejected" {
+		statusKerja = "ditolak"
+	}
+
+	reviewer := c.GetUint("userID")
+	now := time.Now()
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&app).Updates(map[string]interface{}{
+			"status":        status,
+			"reject_reason": reason,
+			"reviewed_by":   reviewer,
+			"reviewed_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.User{}).Where("id = ?", app.UserID).Update("status_kerja", statusKerja).Error
+	})
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	msg := "Pendaftaran kurir FaiExpress kamu disetujui. Silakan login ke aplikasi."
+	if reason != nil {
+		msg = "Pendaftaran kurir FaiExpress kamu ditolak. Alasan: " + *reason
+	}
+	if err := notifier.Send(c.Request.Context(), app.User.Phone, msg); err != nil {
+		middleware.Logf(c, "⚠️ Gagal mengirim notifikasi review kurir: %v", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.application_reviewed"), "status": status})
+}
+
+// GET /api/kurir/documents/expiring?days=30 (admin)
+func GetExpiringKurirDocuments(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		c.Error(apperror.InvalidParameter.With("param", "days"))
+		return
+	}
+
+	var docs []model.KurirDocument
+	if err := config.DB.
+		Where("expires_at IS NOT NULL AND expires_at < ?", time.Now().AddDate(0, 0, days)).
+		Order("expires_at ASC").
+		Find(&docs).Error; err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	response := make([]gin.H, 0, len(docs))
+	for _, d := range docs {
+		response = append(response, gin.H{
+			"kurir_id": d.UserID,
+			"document": dto.NewKurirDocumentView(d),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
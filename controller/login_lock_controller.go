@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+)
+
+// GET /api/login-locks — identifier/IP yang sedang terkunci karena gagal login
+func GetLockedLogins(c *gin.Context) {
+	locks, err := auth.LockedLogins()
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, locks)
+}
+
+// DELETE /api/login-locks/:id — buka satu kunci (identifier atau IP)
+func UnlockLogin(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	if err := auth.UnlockLogin(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Error(apperror.LoginLockNotFound)
+			return
+		}
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.login_unlocked")})
+}
+
+// DELETE /api/users/:id/login-lock — buka kunci login untuk email & HP user
+func UnlockUserLogin(c *gin.Context) {
+	var user model.User
+	if err := config.DB.First(&user, c.Param("id")).Error; err != nil {
+		c.Error(apperror.UserNotFound)
+		return
+	}
+
+	if err := auth.UnlockUser(user); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.user_login_unlocked")})
+}
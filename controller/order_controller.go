@@ -1,82 +1,120 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
 	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
 )
 
+// OrderHandler endpoint pesanan; aturan bisnisnya ada di service.OrderService
+type OrderHandler struct {
+	orders *service.OrderService
+}
+
+func NewOrderHandler(orders *service.OrderService) *OrderHandler {
+	return &OrderHandler{orders: orders}
+}
+
+// CreateOrderRequest satu-satunya data pesanan yang boleh dikirim customer.
+// Pemilik pesanan diambil dari token; status, status bayar dan nominal
+// ditentukan server.
+type CreateOrderRequest struct {
+	KurirID uint   `json:"kurir_id" binding:"required"`
+	Layanan string `json:"layanan" binding:"required,max=100"`
+}
+
 // 🔸 Create Order
-func CreateOrder(c *gin.Context) {
-	var input model.Order
+func (h *OrderHandler) CreateOrder(c *gin.Context) {
+	var input CreateOrderRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(validationError(c, err))
 		return
 	}
 
-	// Validasi kurir_id tidak null atau 0
-	if input.KurirID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Kurir tidak boleh kosong"})
-		return
+	order := model.Order{
+		CustomerID: c.GetUint("userID"),
+		KurirID:    input.KurirID,
+		Layanan:    strings.TrimSpace(input.Layanan),
 	}
-
-	// Set status default
-	if input.Status == "" {
-		input.Status = "proses"
+	err := h.orders.Create(c.Request.Context(), &order)
+	if errors.Is(err, service.ErrKurirRequired) {
+		c.Error(apperror.KurirRequired)
+		return
 	}
-
-	if err := config.DB.Create(&input).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	// Update status kurir jadi offline
-	// if input.KurirID != 0 {
-	// 	config.DB.Model(&model.User{}).Where("id = ?", input.KurirID).Update("status", "offline")
-	// }
-
 	// ✅ Return order ID dan pesan
 	c.JSON(http.StatusCreated, gin.H{
-		"message":  "Pesanan berhasil dibuat",
-		"order_id": input.ID, // ambil ID dari input setelah di-insert
+		"message":  i18n.Msg(c, "msg.order_created"),
+		"order_id": order.ID,
 	})
 }
 
 // 🔸 Get All Orders
-func GetAllOrders(c *gin.Context) {
-	var orders []model.Order
-	if err := config.DB.
-		Preload("Customer").
-		Preload("Kurir").
-		Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+// Filter: ?status=&payment_status=&layanan=&kurir_id=&customer_id=&from=&to=&q=
+// Sort: ?sort=created_at&order=desc, Paging: ?page=1&limit=20
+func (h *OrderHandler) GetAllOrders(c *gin.Context) {
+	filter, ok := parseOrderFilter(c)
+	if !ok {
+		return
+	}
+	sort, desc, ok := parseOrderSort(c)
+	if !ok {
+		return
+	}
+	page, limit, ok := parsePage(c)
+	if !ok {
+		return
+	}
+
+	orders, total, err := h.orders.List(c.Request.Context(), repository.OrderListQuery{
+		Filter: filter,
+		Sort:   sort,
+		Desc:   desc,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
-	c.JSON(http.StatusOK, orders)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":       dto.NewOrderSummaries(orders),
+		"pagination": dto.NewPagination(page, limit, total),
+	})
 }
 
 // 🔸 Get Order by ID
-func GetOrderByID(c *gin.Context) {
-	id := c.Param("id")
-
-	var order model.Order
-	if err := config.DB.
-		Preload("Kurir").
-		Preload("Customer").
-		First(&order, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Pesanan tidak ditemukan"})
+func (h *OrderHandler) GetOrderByID(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orders.Authorize(c.Request.Context(), policy.ActorFrom(c), id, policy.ViewOrder)
+	if err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	var activeCount int64
-	config.DB.Model(&model.Order{}).
-		Where("kurir_id = ? AND status = ?", order.KurirID, "proses").
-		Count(&activeCount)
+	activeCount, err := h.orders.ActiveOrders(c.Request.Context(), order.KurirID)
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
 
 	kurirData := map[string]interface{}{
 		"id":            order.Kurir.ID,
@@ -87,7 +125,7 @@ func GetOrderByID(c *gin.Context) {
 
 	// return response dengan kurir info yang dilengkapi
 	c.JSON(http.StatusOK, gin.H{
-		"order":          order,
+		"order":          dto.NewOrderDetail(order),
 		"kurir":          kurirData,
 		"user_id":        order.Customer.ID, // ✅ tambahkan ini
 		"payment_status": order.PaymentStatus,
@@ -96,101 +134,89 @@ func GetOrderByID(c *gin.Context) {
 	})
 }
 
-func DeleteMessagesByOrderID(c *gin.Context) {
-	orderID := c.Param("id")
-
-	if err := config.DB.Where("order_id = ?", orderID).Delete(&model.Message{}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus pesan"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Semua pesan berhasil dihapus"})
-}
-
-func UpdatePaymentMethod(c *gin.Context) {
-	id := c.Param("id")
+func (h *OrderHandler) UpdatePaymentMethod(c *gin.Context) {
 	var req struct {
 		Method string `json:"metode_bayar"`
 	}
 	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	var order model.Order
-	if err := config.DB.First(&order, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
-
-	order.MetodeBayar = req.Method // ✅ langsung assign string, bukan pointer
-
-	if err := config.DB.Save(&order).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
+	if err := h.orders.ChoosePaymentMethod(c.Request.Context(), policy.ActorFrom(c), id, req.Method); err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Metode bayar diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.payment_method_updated")})
 }
 
 // 🔸 Update Order
-func UpdateOrder(c *gin.Context) {
-	id := c.Param("id")
-	var order model.Order
-
-	if err := config.DB.First(&order, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+func (h *OrderHandler) UpdateOrder(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
-
-	if err := c.ShouldBindJSON(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	order, err := h.orders.Authorize(c.Request.Context(), policy.ActorFrom(c), id, policy.UpdateOrderStatus)
+	if err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	if err := config.DB.Save(&order).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if policy.EditOrder(policy.ActorFrom(c), order) {
+		if err := c.ShouldBindJSON(&order); err != nil {
+			c.Error(validationError(c, err))
+			return
+		}
+		order.ID = id
+	} else {
+		// Kurir yang ditugaskan hanya boleh mengubah status
+		var input struct {
+			Status string `json:"status" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.Error(validationError(c, err))
+			return
+		}
+		order.Status = input.Status
 	}
 
-	if order.Status == "selesai" {
-		config.DB.Model(&model.User{}).Where("id = ?", order.KurirID).Update("status", "online")
+	if err := h.orders.Save(c.Request.Context(), &order); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
 	}
 
-	c.JSON(http.StatusOK, order)
+	c.JSON(http.StatusOK, dto.NewOrderSummary(order))
 }
 
 // 🔸 Delete Order
-func DeleteOrder(c *gin.Context) {
-	id := c.Param("id")
-	i, err := strconv.Atoi(id)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+func (h *OrderHandler) DeleteOrder(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
 
-	if err := config.DB.Delete(&model.Order{}, i).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.orders.Delete(c.Request.Context(), id); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Order berhasil dihapus"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.order_deleted")})
 }
 
 // 🔸 Get My Orders (Customer)
-func GetMyOrders(c *gin.Context) {
-	userID := c.GetUint("userID")
-	var orders []model.Order
-
-	if err := config.DB.
-		Preload("Kurir"). // ⬅️ PENTING: preload kurir
-		Where("customer_id = ?", userID).
-		Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+func (h *OrderHandler) GetMyOrders(c *gin.Context) {
+	orders, err := h.orders.ListByCustomer(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, orders)
+	c.JSON(http.StatusOK, dto.NewOrderSummaries(orders))
 }
 
 func UpdateLocation(c *gin.Context) {
@@ -200,12 +226,12 @@ func UpdateLocation(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&loc); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format lokasi salah"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
 	// Simpan lokasi ke cache, Redis, atau memori (dummy response dulu)
-	c.JSON(http.StatusOK, gin.H{"message": "Lokasi diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.location_updated")})
 }
 
 // 🔸 Send Chat
@@ -217,19 +243,19 @@ func SendChat(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&msg); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Format chat salah"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
 	// Dummy response, seharusnya disimpan ke DB atau cache
-	c.JSON(http.StatusOK, gin.H{"message": "Pesan terkirim"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.chat_sent")})
 }
 
 // 🔸 Get Chat (Customer Side)
 func GetChat(c *gin.Context) {
 	orderID := c.Query("order_id")
 	if orderID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id wajib diisi"})
+		c.Error(apperror.MissingParameter.With("param", "order_id"))
 		return
 	}
 
@@ -242,42 +268,37 @@ func GetChat(c *gin.Context) {
 	})
 }
 
-func UpdateOrderStatus(c *gin.Context) {
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	var input struct {
 		ID     uint   `json:"id"`
 		Status string `json:"status"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	// Pakai struct agar UpdatedAt ikut berubah otomatis
-	updateData := model.Order{
-		Status: input.Status,
-	}
-
-	if err := config.DB.Model(&model.Order{}).
-		Where("id = ?", input.ID).
-		Updates(updateData).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update status"})
+	if err := h.orders.UpdateStatus(c.Request.Context(), policy.ActorFrom(c), input.ID, input.Status); err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Status berhasil diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.status_updated")})
 }
 
-func CheckOrderKurirReady(c *gin.Context) {
-	orderID := c.Param("id")
-	var order model.Order
-
-	if err := config.DB.First(&order, orderID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order tidak ditemukan"})
+func (h *OrderHandler) CheckOrderKurirReady(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
+		return
+	}
+	order, err := h.orders.Authorize(c.Request.Context(), policy.ActorFrom(c), id, policy.ViewOrder)
+	if err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	tagihanSiap := *order.Nominal > 0
+	tagihanSiap := order.Nominal != nil && *order.Nominal > 0
 	metodeBayarDiisi := order.MetodeBayar != ""
 
 	c.JSON(http.StatusOK, gin.H{
@@ -287,7 +308,7 @@ func CheckOrderKurirReady(c *gin.Context) {
 	})
 }
 
-func UpdateTagihan(c *gin.Context) {
+func (h *OrderHandler) UpdateTagihan(c *gin.Context) {
 	type RincianItem struct {
 		Judul   string `json:"judul"`
 		Nominal int    `json:"nominal"`
@@ -300,24 +321,12 @@ func UpdateTagihan(c *gin.Context) {
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
-		return
-	}
-
-	var order model.Order
-	if err := config.DB.First(&order, req.ID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	nominal := uint(req.Nominal)
-	status := "pending"
-
-	order.Nominal = &nominal
-	order.PaymentStatus = &status
-
-	if err := config.DB.Save(&order).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
+	if err := h.orders.Bill(c.Request.Context(), policy.ActorFrom(c), req.ID, uint(req.Nominal)); err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
@@ -327,38 +336,37 @@ func UpdateTagihan(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Tagihan berhasil diperbarui (rincian tidak disimpan)",
+		"message": i18n.Msg(c, "msg.billing_updated"),
 	})
 }
 
-func ValidasiPembayaran(c *gin.Context) {
+func (h *OrderHandler) ValidasiPembayaran(c *gin.Context) {
 	var req struct {
 		ID uint `json:"id"`
 	}
 
 	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	if err := config.DB.Model(&model.Order{}).
-		Where("id = ?", req.ID).
-		Update("payment_status", "done").Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update status pembayaran"})
+	if err := h.orders.ValidatePayment(c.Request.Context(), policy.ActorFrom(c), req.ID); err != nil {
+		respondOrderError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Pembayaran divalidasi"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.payment_validated")})
 }
 
-func GetOrdersProses(c *gin.Context) {
-	kurirID := c.Param("id")
-	var orders []model.Order
+func (h *OrderHandler) GetOrdersProses(c *gin.Context) {
+	kurirID, ok := authorizeKurir(c, policy.ViewKurirOrders)
+	if !ok {
+		return
+	}
 
-	if err := config.DB.Preload("Customer").
-		Where("kurir_id = ? AND status = ?", kurirID, "proses").
-		Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal ambil data order"})
+	orders, err := h.orders.ListByKurir(c.Request.Context(), kurirID, "proses")
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
@@ -377,43 +385,26 @@ func GetOrdersProses(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func GetTotalPendapatanToday(c *gin.Context) {
-	var totalPendapatan float64
-
-	// Ambil waktu sekarang
-	now := time.Now()
-	// Tentukan awal dan akhir hari
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Second)
-
-	// Hitung total biaya dari order yang statusnya selesai hari ini
-	err := config.DB.Model(&model.Order{}).
-		Where("status = ? AND updated_at BETWEEN ? AND ?", "selesai", startOfDay, endOfDay).
-		Select("COALESCE(SUM(biaya), 0)"). // pakai COALESCE supaya hasilnya 0 kalau tidak ada data
-		Scan(&totalPendapatan).Error
-
+// Pendapatan hari ini (WIB) dari semua pesanan selesai
+func (h *OrderHandler) GetTotalPendapatanToday(c *gin.Context) {
+	totalPendapatan, err := h.orders.RevenueToday(c.Request.Context(), 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung total pendapatan"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"total_pendapatan": totalPendapatan})
 }
 
-func GetPendapatanKurirToday(c *gin.Context) {
-	kurirID := c.Param("id")
-	today := time.Now().Format("2006-01-02")
-
-	var totalPendapatan float64
-
-	err := config.DB.
-		Model(&model.Order{}).
-		Select("COALESCE(SUM(nominal), 0)").
-		Where("kurir_id = ? AND status = ? AND DATE(updated_at) = ?", kurirID, "selesai", today).
-		Scan(&totalPendapatan).Error
+func (h *OrderHandler) GetPendapatanKurirToday(c *gin.Context) {
+	kurirID, ok := authorizeKurir(c, policy.ViewKurirOrders)
+	if !ok {
+		return
+	}
 
+	totalPendapatan, err := h.orders.RevenueToday(c.Request.Context(), kurirID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil data pendapatan"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
@@ -422,20 +413,10 @@ func GetPendapatanKurirToday(c *gin.Context) {
 	})
 }
 
-func GetAllTotalPendapatanToday(c *gin.Context) {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	today := time.Now().In(loc).Truncate(24 * time.Hour)
-	tomorrow := today.Add(24 * time.Hour)
-
-	var totalPendapatan float64
-
-	err := config.DB.Model(&model.Order{}).
-		Where("status = ? AND updated_at BETWEEN ? AND ?", "selesai", today, tomorrow).
-		Select("COALESCE(SUM(nominal), 0)"). // pakai nominal karena kamu pakai itu untuk tagihan kurir
-		Scan(&totalPendapatan).Error
-
+func (h *OrderHandler) GetAllTotalPendapatanToday(c *gin.Context) {
+	totalPendapatan, err := h.orders.RevenueToday(c.Request.Context(), 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung total pendapatan"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
@@ -444,18 +425,10 @@ func GetAllTotalPendapatanToday(c *gin.Context) {
 	})
 }
 
-func GetTotalOrdersSelesaiToday(c *gin.Context) {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	today := time.Now().In(loc).Truncate(24 * time.Hour)
-	tomorrow := today.Add(24 * time.Hour)
-
-	var totalOrders int64
-	err := config.DB.Model(&model.Order{}).
-		Where("status = ? AND updated_at BETWEEN ? AND ?", "selesai", today, tomorrow).
-		Count(&totalOrders).Error
-
+func (h *OrderHandler) GetTotalOrdersSelesaiToday(c *gin.Context) {
+	totalOrders, err := h.orders.CompletedToday(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghitung pesanan selesai"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
@@ -464,18 +437,15 @@ func GetTotalOrdersSelesaiToday(c *gin.Context) {
 	})
 }
 
-func GetOrdersSelesaiToday(c *gin.Context) {
-	kurirID := c.Param("id")
-	var orders []model.Order
-
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	today := time.Now().In(loc).Truncate(24 * time.Hour)
-	tomorrow := today.Add(24 * time.Hour)
+func (h *OrderHandler) GetOrdersSelesaiToday(c *gin.Context) {
+	kurirID, ok := authorizeKurir(c, policy.ViewKurirOrders)
+	if !ok {
+		return
+	}
 
-	if err := config.DB.Preload("Customer").
-		Where("kurir_id = ? AND status = ? AND updated_at BETWEEN ? AND ?", kurirID, "selesai", today, tomorrow).
-		Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal ambil data order selesai hari ini"})
+	orders, err := h.orders.CompletedTodayByKurir(c.Request.Context(), kurirID)
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
@@ -493,15 +463,16 @@ func GetOrdersSelesaiToday(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func GetOrdersForKurir(c *gin.Context) {
-	kurirID := c.Param("id")
-	var orders []model.Order
+func (h *OrderHandler) GetOrdersForKurir(c *gin.Context) {
+	kurirID, ok := authorizeKurir(c, policy.ViewKurirOrders)
+	if !ok {
+		return
+	}
 
 	// Ambil semua pesanan kurir (proses dan selesai)
-	if err := config.DB.Preload("Customer").
-		Where("kurir_id = ?", kurirID).
-		Find(&orders).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal ambil data order"})
+	orders, err := h.orders.ListByKurir(c.Request.Context(), kurirID, "")
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
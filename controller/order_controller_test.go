@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+)
+
+// orderFixture dua customer, masing-masing dengan satu pesanan yang diantar
+// kurir berbeda, di atas repository memori
+type orderFixture struct {
+	router               *gin.Engine
+	customerA, customerB policy.Actor
+	kurirA, kurirB       policy.Actor
+	orderA, orderB       model.Order
+	repos                repository.Repositories
+}
+
+func newOrderFixture(t *testing.T) *orderFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	f := &orderFixture{repos: repository.NewMemory()}
+	for _, u := range []struct {
+		name, role string
+		actor      *policy.Actor
+	}{
+		{"customer-a", "customer", &f.customerA},
+		{"customer-b", "customer", &f.customerB},
+		{"kurir-a", "kurir", &f.kurirA},
+		{"kurir-b", "kurir", &f.kurirB},
+	} {
+		user := model.User{Name: u.name, Email: u.name + "@example.com", Role: u.role, StatusKerja: "aktif"}
+		if err := f.repos.Users.Save(ctx, &user); err != nil {
+			t.Fatal(err)
+		}
+		*u.actor = policy.Actor{ID: user.ID, Role: u.role}
+	}
+
+	svc := service.New(f.repos, service.PublisherFunc(func(context.Context, string, interface{}) error { return nil }),
+		func(uint, uint) error { return nil })
+	f.orderA = model.Order{CustomerID: f.customerA.ID, KurirID: f.kurirA.ID, Layanan: "antar barang"}
+	f.orderB = model.Order{CustomerID: f.customerB.ID, KurirID: f.kurirB.ID, Layanan: "antar barang"}
+	for _, o := range []*model.Order{&f.orderA, &f.orderB} {
+		if err := svc.Orders.Create(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	orders := NewOrderHandler(svc.Orders)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), asActor)
+	r.POST("/api/orders", orders.CreateOrder)
+	r.GET("/api/my-orders", orders.GetMyOrders)
+	r.GET("/api/orders/:id", orders.GetOrderByID)
+	r.PUT("/api/orders/:id", orders.UpdateOrder)
+	r.PUT("/api/orders/:id/metode_bayar", orders.UpdatePaymentMethod)
+	r.GET("/api/kurir/:id/orders", orders.GetOrdersForKurir)
+	f.router = r
+	return f
+}
+
+// asActor pengganti JWTAuthMiddleware: identitas diambil dari header test
+func asActor(c *gin.Context) {
+	var id uint
+	fmt.Sscan(c.GetHeader("X-Test-User"), &id)
+	c.Set("userID", id)
+	c.Set("role", c.GetHeader("X-Test-Role"))
+	c.Next()
+}
+
+func (f *orderFixture) do(a policy.Actor, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", fmt.Sprint(a.ID))
+	req.Header.Set("X-Test-Role", a.Role)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOrderOwnership(t *testing.T) {
+	f := newOrderFixture(t)
+	orderA := fmt.Sprintf("/api/orders/%d", f.orderA.ID)
+	orderB := fmt.Sprintf("/api/orders/%d", f.orderB.ID)
+	status := map[string]string{"status": "selesai"}
+	method := map[string]string{"metode_bayar": "cash"}
+
+	cases := []struct {
+		name   string
+		actor  policy.Actor
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"customer reads own order", f.customerA, http.MethodGet, orderA, nil, http.StatusOK},
+		{"customer cannot read other's order", f.customerA, http.MethodGet, orderB, nil, http.StatusForbidden},
+		{"customer cannot edit own order", f.customerA, http.MethodPut, orderA, status, http.StatusForbidden},
+		{"customer cannot edit other's order", f.customerA, http.MethodPut, orderB, status, http.StatusForbidden},
+		{"customer picks payment method on own order", f.customerA, http.MethodPut, orderA + "/metode_bayar", method, http.StatusOK},
+		{"customer cannot pick payment method on other's order", f.customerA, http.MethodPut, orderB + "/metode_bayar", method, http.StatusForbidden},
+		{"kurir reads assigned order", f.kurirA, http.MethodGet, orderA, nil, http.StatusOK},
+		{"kurir cannot read unassigned order", f.kurirA, http.MethodGet, orderB, nil, http.StatusForbidden},
+		{"kurir updates assigned order status", f.kurirA, http.MethodPut, orderA, status, http.StatusOK},
+		{"kurir cannot update unassigned order", f.kurirA, http.MethodPut, orderB, status, http.StatusForbidden},
+		{"kurir cannot pick payment method on unassigned order", f.kurirA, http.MethodPut, orderB + "/metode_bayar", method, http.StatusForbidden},
+		{"kurir lists own orders", f.kurirA, http.MethodGet, fmt.Sprintf("/api/kurir/%d/orders", f.kurirA.ID), nil, http.StatusOK},
+		{"kurir cannot list another kurir's orders", f.kurirA, http.MethodGet, fmt.Sprintf("/api/kurir/%d/orders", f.kurirB.ID), nil, http.StatusForbidden},
+		{"unknown order", f.customerA, http.MethodGet, "/api/orders/999", nil, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := f.do(tc.actor, tc.method, tc.path, tc.body); w.Code != tc.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+}
+
+func TestMyOrdersOnlyOwn(t *testing.T) {
+	f := newOrderFixture(t)
+
+	w := f.do(f.customerA, http.MethodGet, "/api/my-orders", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var orders []struct {
+		ID uint `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &orders); err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].ID != f.orderA.ID {
+		t.Fatalf("got %+v, want only order %d", orders, f.orderA.ID)
+	}
+}
+
+// Pemilik pesanan selalu dari token; status dan tagihan ditentukan server
+func TestCreateOrderIgnoresClientOwnedFields(t *testing.T) {
+	f := newOrderFixture(t)
+
+	w := f.do(f.customerA, http.MethodPost, "/api/orders", map[string]interface{}{
+		"customer_id":    f.customerB.ID,
+		"kurir_id":       f.kurirA.ID,
+		"layanan":        "antar dokumen",
+		"status":         "selesai",
+		"payment_status": "lunas",
+		"nominal":        1,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var res struct {
+		OrderID uint `json:"order_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := f.repos.Orders.FindByID(context.Background(), res.OrderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.CustomerID != f.customerA.ID || order.Status != "proses" || order.PaymentStatus != nil || order.Nominal != nil {
+		t.Fatalf("client-owned fields leaked into order: %+v", order)
+	}
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+// parseOrderFilter membaca ?status=&payment_status=&layanan=&kurir_id=&customer_id=&from=&to=&q=
+// from/to berformat YYYY-MM-DD (WIB), to inklusif
+func parseOrderFilter(c *gin.Context) (repository.OrderFilter, bool) {
+	f := repository.OrderFilter{
+		Status:        c.Query("status"),
+		PaymentStatus: c.Query("payment_status"),
+		Layanan:       c.Query("layanan"),
+		Search:        strings.TrimSpace(c.Query("q")),
+	}
+
+	for _, p := range []struct {
+		key  string
+		dest *uint
+	}{{"kurir_id", &f.KurirID}, {"customer_id", &f.CustomerID}} {
+		if s := c.Query(p.key); s != "" {
+			id, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				c.Error(apperror.InvalidParameter.With("param", p.key))
+				return f, false
+			}
+			*p.dest = uint(id)
+		}
+	}
+
+	loc, _ := time.LoadLocation("Asia/Jakarta")
+	if s := c.Query("from"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, loc)
+		if err != nil {
+			c.Error(apperror.InvalidDate.With("param", "from"))
+			return f, false
+		}
+		f.From = &t
+	}
+	if s := c.Query("to"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, loc)
+		if err != nil {
+			c.Error(apperror.InvalidDate.With("param", "to"))
+			return f, false
+		}
+		end := t.AddDate(0, 0, 1)
+		f.To = &end
+	}
+
+	return f, true
+}
+
+// parseOrderSort membaca ?sort=kolom&order=asc|desc (default created_at desc)
+func parseOrderSort(c *gin.Context) (sort string, desc bool, ok bool) {
+	sort = c.DefaultQuery("sort", "created_at")
+	if _, ok := repository.OrderSortColumns[sort]; !ok {
+		c.Error(apperror.InvalidSort)
+		return "", false, false
+	}
+
+	direction := strings.ToUpper(c.DefaultQuery("order", "desc"))
+	if direction != "ASC" && direction != "DESC" {
+		c.Error(apperror.InvalidSortOrder)
+		return "", false, false
+	}
+	return sort, direction == "DESC", true
+}
+
+// parsePage membaca ?page=&limit= (default 1 dan 20, limit maksimal 100)
+func parsePage(c *gin.Context) (page, limit int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.Error(apperror.InvalidParameter.With("param", "page"))
+		return 0, 0, false
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 {
+		c.Error(apperror.InvalidParameter.With("param", "limit"))
+		return 0, 0, false
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit, true
+}
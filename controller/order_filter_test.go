@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+)
+
+func queryContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/orders?"+query, nil)
+	return c
+}
+
+// lastCode kode apperror terakhir yang ditempel handler
+func lastCode(c *gin.Context) string {
+	if len(c.Errors) == 0 {
+		return ""
+	}
+	return apperror.From(c.Errors.Last().Err).Code
+}
+
+func TestParseOrderFilterDates(t *testing.T) {
+	wib, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("tzdata Asia/Jakarta tidak tersedia")
+	}
+
+	c := queryContext("from=2025-03-01&to=2025-03-31&q=+budi+&kurir_id=7")
+	f, ok := parseOrderFilter(c)
+	if !ok {
+		t.Fatalf("rejected: %v", c.Errors)
+	}
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, wib); !f.From.Equal(want) {
+		t.Errorf("from %v, want %v", f.From, want)
+	}
+	// to inklusif: batas atas eksklusif di awal hari berikutnya
+	if want := time.Date(2025, 4, 1, 0, 0, 0, 0, wib); !f.To.Equal(want) {
+		t.Errorf("to %v, want %v", f.To, want)
+	}
+	if f.Search != "budi" || f.KurirID != 7 {
+		t.Errorf("search %q kurir %d", f.Search, f.KurirID)
+	}
+}
+
+func TestParseOrderFilterRejects(t *testing.T) {
+	cases := []struct {
+		query string
+		code  string
+	}{
+		{"from=01-03-2025", "invalid_date"},
+		{"to=2025-02-30", "invalid_date"},
+		{"from=2025-03-01T00:00:00Z", "invalid_date"},
+		{"kurir_id=abc", "invalid_parameter"},
+		{"customer_id=-1", "invalid_parameter"},
+	}
+	for _, tc := range cases {
+		c := queryContext(tc.query)
+		if _, ok := parseOrderFilter(c); ok || lastCode(c) != tc.code {
+			t.Errorf("%s: ok %v code %q, want %q", tc.query, ok, lastCode(c), tc.code)
+		}
+	}
+}
+
+func TestParseOrderSort(t *testing.T) {
+	cases := []struct {
+		query string
+		sort  string
+		desc  bool
+		code  string
+	}{
+		{"", "created_at", true, ""},
+		{"sort=nominal&order=asc", "nominal", false, ""},
+		{"sort=payment_status&order=DESC", "payment_status", true, ""},
+		{"sort=customer.password", "", false, "invalid_sort"},
+		{"sort=orders.id%3BDROP+TABLE+orders", "", false, "invalid_sort"},
+		{"sort=id&order=sideways", "", false, "invalid_sort_order"},
+	}
+	for _, tc := range cases {
+		c := queryContext(tc.query)
+		sort, desc, ok := parseOrderSort(c)
+		if ok != (tc.code == "") || lastCode(c) != tc.code {
+			t.Errorf("%q: ok %v code %q, want %q", tc.query, ok, lastCode(c), tc.code)
+			continue
+		}
+		if sort != tc.sort || desc != tc.desc {
+			t.Errorf("%q: sort %q desc %v, want %q %v", tc.query, sort, desc, tc.sort, tc.desc)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/otp"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// otpRequestLimit membatasi permintaan kode login per nomor. Dicek sebelum
+// user dicari supaya nomor terdaftar dan tidak terdaftar kena batas yang sama.
+var otpRequestLimit = ratelimit.Policy{Name: "otp-login", Limit: 5, Period: time.Hour, Burst: 3}
+
+// POST /auth/otp/request — minta kode OTP untuk login
+func RequestLoginOTP(c *gin.Context) {
+	var input struct {
+		Phone string `json:"phone" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	phone := utils.NormalizePhone(input.Phone)
+
+	if res, err := ratelimit.Take(c.Request.Context(), otpRequestLimit, phone); err != nil {
+		middleware.Logf(c, "⚠️ Rate limit store error: %v", err)
+	} else if !res.Allowed {
+		c.Header("Retry-After", ratelimit.RetryAfterHeader(res))
+		c.Error(apperror.OTPTooSoon)
+		return
+	}
+
+	// Respon sama untuk nomor terdaftar maupun tidak, supaya nomor tidak bisa ditebak.
+	// Hanya nomor yang sudah diverifikasi pemiliknya yang bisa dipakai login.
+	var user model.User
+	if err := config.DB.Where("phone = ? AND status_kerja = ? AND phone_verified_at IS NOT NULL", phone, "aktif").First(&user).Error; err == nil {
+		// Batas kirim di otp.Issue hanya berlaku untuk nomor terdaftar, jadi
+		// errornya tidak boleh sampai ke client
+		if err := otp.Issue(c.Request.Context(), phone, otp.PurposeLogin); err != nil && !otpThrottled(err) {
+			middleware.Logf(c, "⚠️ Gagal mengirim OTP login: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.otp_sent_if_registered")})
+}
+
+// otpThrottled kode tidak dikirim karena batas kirim per nomor
+func otpThrottled(err error) bool {
+	return errors.Is(err, otp.ErrTooSoon) || errors.Is(err, otp.ErrTooMany)
+}
+
+// POST /auth/otp/login — login dengan kode OTP, token sama seperti /login
+func LoginWithOTP(c *gin.Context) {
+	var input struct {
+		Phone      string `json:"phone" binding:"required"`
+		Code       string `json:"code" binding:"required"`
+		DeviceName string `json:"device_name"`
+		Platform   string `json:"platform"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	phone := utils.NormalizePhone(input.Phone)
+	if err := otp.Verify(phone, otp.PurposeLogin, input.Code); err != nil {
+		respondOTPError(c, err)
+		return
+	}
+
+	var user model.User
+	if err := config.DB.Where("phone = ? AND phone_verified_at IS NOT NULL", phone).First(&user).Error; err != nil {
+		c.Error(apperror.OTPInvalid)
+		return
+	}
+
+	if user.StatusKerja != "aktif" {
+		c.Error(apperror.AccountInactive)
+		return
+	}
+
+	respondLogin(c, user, input.DeviceName, input.Platform)
+}
+
+// POST /api/phone/verify/request — kirim OTP ke nomor user yang sedang login
+func RequestPhoneVerification(c *gin.Context) {
+	var user model.User
+	if err := config.DB.First(&user, c.GetUint("userID")).Error; err != nil {
+		c.Error(apperror.UserNotFound)
+		return
+	}
+
+	if user.Phone == "" {
+		c.Error(apperror.PhoneMissing)
+		return
+	}
+	if user.PhoneVerifiedAt != nil {
+		c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.phone_already_verified")})
+		return
+	}
+
+	if err := otp.Issue(c.Request.Context(), utils.NormalizePhone(user.Phone), otp.PurposeVerifyPhone); err != nil {
+		respondOTPError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.otp_sent")})
+}
+
+// POST /api/phone/verify — konfirmasi kode verifikasi nomor
+func VerifyPhone(c *gin.Context) {
+	var input struct {
+		Code string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, c.GetUint("userID")).Error; err != nil {
+		c.Error(apperror.UserNotFound)
+		return
+	}
+
+	if err := otp.Verify(utils.NormalizePhone(user.Phone), otp.PurposeVerifyPhone, input.Code); err != nil {
+		respondOTPError(c, err)
+		return
+	}
+
+	if err := config.DB.Model(&user).Update("phone_verified_at", time.Now()).Error; err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.phone_verified")})
+}
+
+func respondOTPError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, otp.ErrTooSoon):
+		c.Error(apperror.OTPTooSoon)
+	case errors.Is(err, otp.ErrTooMany):
+		c.Error(apperror.OTPTooMany)
+	case errors.Is(err, otp.ErrInvalidCode):
+		c.Error(apperror.OTPInvalid)
+	case errors.Is(err, otp.ErrNoAttempts):
+		c.Error(apperror.OTPExhausted)
+	default:
+		c.Error(apperror.Internal.Wrap(err))
+	}
+}
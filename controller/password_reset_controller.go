@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/notifier"
+	"github.com/mubarok-ridho/misi-paket.backend/otp"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// resetRequestLimit membatasi permintaan reset per email/nomor, dihitung
+// sebelum user dicari sehingga akun ada atau tidak diperlakukan sama
+var resetRequestLimit = ratelimit.Policy{Name: "forgot-password", Limit: 5, Period: time.Hour, Burst: 3}
+
+// POST /auth/forgot-password — kirim link reset (email) atau kode OTP (HP)
+func ForgotPassword(c *gin.Context) {
+	var input struct {
+		Identifier string `json:"email" binding:"required"` // email atau no HP, sama seperti login
+		Channel    string `json:"channel"`                  // "email" (default) atau "otp"
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+	if input.Channel != "" && input.Channel != "email" && input.Channel != "otp" {
+		c.Error(apperror.ResetChannel)
+		return
+	}
+
+	// Respon selalu sama supaya email/nomor terdaftar tidak bisa ditebak,
+	// termasuk saat batas permintaan tercapai
+	okResponse := gin.H{"message": i18n.Msg(c, "msg.reset_instructions")}
+	identifier := auth.LoginIdentifier(input.Identifier)
+
+	if res, err := ratelimit.Take(c.Request.Context(), resetRequestLimit, identifier); err != nil {
+		middleware.Logf(c, "⚠️ Rate limit store error: %v", err)
+	} else if !res.Allowed {
+		c.JSON(http.StatusOK, okResponse)
+		return
+	}
+
+	var user model.User
+	if err := config.DB.
+		Where("LOWER(email) = ? OR phone = ?", identifier, identifier).
+		First(&user).Error; err != nil {
+		c.JSON(http.StatusOK, okResponse)
+		return
+	}
+
+	switch input.Channel {
+	case "", "email":
+		if err := sendResetLink(c.Request.Context(), user, nil); err != nil && !errors.Is(err, auth.ErrResetTooSoon) {
+			middleware.Logf(c, "⚠️ Gagal mengirim link reset: %v", err)
+		}
+	case "otp":
+		if user.Phone == "" {
+			break
+		}
+		err := otp.Issue(c.Request.Context(), utils.NormalizePhone(user.Phone), otp.PurposeResetPassword)
+		if err != nil && !otpThrottled(err) {
+			middleware.Logf(c, "⚠️ Gagal mengirim OTP reset: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, okResponse)
+}
+
+// POST /auth/reset-password — pakai {token} dari email atau {phone, code} dari OTP
+func ResetPassword(c *gin.Context) {
+	var input struct {
+		Token       string `json:"token"`
+		Phone       string `json:"phone"`
+		Code        string `json:"code"`
+		NewPassword string `json:"new_password" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	var err error
+	switch {
+	case input.Token != "":
+		err = auth.ResetPasswordWithToken(input.Token, input.NewPassword)
+	case input.Phone != "" && input.Code != "":
+		phone := utils.NormalizePhone(input.Phone)
+
+		var user model.User
+		if err := config.DB.Where("phone = ?", phone).First(&user).Error; err != nil {
+			c.Error(apperror.OTPInvalid)
+			return
+		}
+
+		// Cek aturan password dulu supaya kode OTP tidak hangus sia-sia
+		if err := utils.ValidatePassword(input.NewPassword, user.Name, user.Email); err != nil {
+			c.Error(passwordError(err))
+			return
+		}
+
+		if err := otp.Verify(phone, otp.PurposeResetPassword, input.Code); err != nil {
+			respondOTPError(c, err)
+			return
+		}
+		err = auth.ResetPassword(user.ID, input.NewPassword)
+	default:
+		c.Error(apperror.ResetCredentials)
+		return
+	}
+
+	var policyErr auth.PolicyError
+	switch {
+	case errors.Is(err, auth.ErrResetInvalid):
+		c.Error(apperror.ResetTokenInvalid)
+		return
+	case errors.Is(err, auth.ErrPasswordReused):
+		c.Error(apperror.PasswordReused)
+		return
+	case errors.As(err, &policyErr):
+		c.Error(passwordError(policyErr.Err))
+		return
+	case err != nil:
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.password_reset_done")})
+}
+
+// POST /api/users/:id/reset-password (admin) — kirim link reset ke user, mis. kurir
+func AdminResetPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	var user model.User
+	if err := config.DB.First(&user, id).Error; err != nil {
+		c.Error(apperror.UserNotFound)
+		return
+	}
+
+	if user.Email == "" {
+		c.Error(apperror.UserNoEmail)
+		return
+	}
+
+	adminID := c.GetUint("userID")
+	if err := sendResetLink(c.Request.Context(), user, &adminID); err != nil {
+		if errors.Is(err, auth.ErrResetTooSoon) {
+			c.Error(apperror.ResetRecentlySent)
+			return
+		}
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.reset_link_sent")})
+}
+
+func sendResetLink(ctx context.Context, user model.User, requestedBy *uint) error {
+	token, err := auth.CreatePasswordReset(user.ID, requestedBy)
+	if err != nil {
+		return err
+	}
+
+	// RESET_PASSWORD_URL bisa berupa deep link aplikasi atau halaman web
+	link := config.Get().ResetURL + "?token=" + url.QueryEscape(token)
+
+	body := fmt.Sprintf("Halo %s,\n\nBuka link berikut untuk membuat password baru (berlaku 30 menit):\n%s\n\n"+
+		"Abaikan email ini kalau kamu tidak meminta reset password.", user.Name, link)
+	return notifier.SendEmail(ctx, user.Email, "Reset password FaiExpress", body)
+}
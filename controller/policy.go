@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// paramID membaca :id di path. Kalau gagal, error sudah dicatat di c.
+func paramID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondOrderError menerjemahkan error OrderService; selain not found dan
+// forbidden dianggap error server
+func respondOrderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		c.Error(apperror.OrderNotFound)
+	case errors.Is(err, service.ErrForbidden):
+		c.Error(apperror.OrderForbidden)
+	default:
+		c.Error(apperror.Internal.Wrap(err))
+	}
+}
+
+// validationError error binding dengan pesan per field dalam bahasa request
+func validationError(c *gin.Context, err error) *apperror.Error {
+	lang := i18n.FromContext(c)
+	return apperror.ValidationFailed.
+		WithMessage(utils.ValidationMessage(lang, err)).
+		WithFields(utils.ValidationErrors(lang, err))
+}
+
+// passwordError pelanggaran kebijakan password, ditempel ke field password
+func passwordError(err error) *apperror.Error {
+	e := apperror.WeakPassword.OnField("password")
+	var pe *utils.PasswordError
+	if errors.As(err, &pe) {
+		e = e.WithKey(pe.Key)
+		for k, v := range pe.Params {
+			e = e.With(k, v)
+		}
+	}
+	return e
+}
+
+// authorizeKurir membaca :id kurir di path lalu mengecek policy-nya
+func authorizeKurir(c *gin.Context, allow func(policy.Actor, uint) bool) (uint, bool) {
+	id, ok := paramID(c)
+	if !ok {
+		return 0, false
+	}
+
+	if !allow(policy.ActorFrom(c), id) {
+		c.Error(apperror.KurirForbidden)
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
+	"gorm.io/gorm"
+)
+
+// GET /api/permissions — katalog permission
+func GetPermissionCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, permission.Catalog)
+}
+
+// GET /api/roles — semua role beserta permission yang berlaku
+func GetRoles(c *gin.Context) {
+	roles, err := permission.ListRoles()
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	response := make([]gin.H, 0, len(roles))
+	for _, r := range roles {
+		response = append(response, gin.H{
+			"name":        r.Name,
+			"description": r.Description,
+			"built_in":    r.BuiltIn,
+			"permissions": permission.Permissions(r.Name),
+		})
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /api/roles — buat role baru, mis. "finance_cabang"
+func CreateRole(c *gin.Context) {
+	var input struct {
+		Name        string   `json:"name" binding:"required,min=3,max=30"`
+		Description string   `json:"description" binding:"max=255"`
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	// Role baru tidak boleh berisi akses yang tidak dimiliki pembuatnya
+	if !permission.CoversAll(c.GetString("role"), input.Permissions) {
+		c.Error(apperror.RoleEscalation)
+		return
+	}
+
+	if permission.RoleExists(input.Name) {
+		c.Error(apperror.RoleExists)
+		return
+	}
+
+	if err := permission.CreateRole(input.Name, input.Description, input.Permissions); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": i18n.Msg(c, "msg.role_created")})
+}
+
+// PUT /api/roles/:name/permissions — ganti seluruh permission role
+func UpdateRolePermissions(c *gin.Context) {
+	var input struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(apperror.InvalidRequest)
+		return
+	}
+
+	// Sama seperti AssignUserRole: role yang diubah dan isinya yang baru harus
+	// tercakup akses pemanggil, termasuk role pemanggil sendiri
+	callerRole, role := c.GetString("role"), c.Param("name")
+	if !permission.Covers(callerRole, role) || !permission.CoversAll(callerRole, input.Permissions) {
+		c.Error(apperror.RoleEscalation)
+		return
+	}
+
+	if err := permission.SetPermissions(role, input.Permissions); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.role_permissions_saved")})
+}
+
+// DELETE /api/roles/:name
+func DeleteRole(c *gin.Context) {
+	if err := permission.DeleteRole(c.Param("name")); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.role_deleted")})
+}
+
+// PUT /api/users/:id/role — pindahkan user ke role lain
+func AssignUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
+		return
+	}
+
+	if !permission.RoleExists(input.Role) {
+		c.Error(apperror.UnknownRole)
+		return
+	}
+	if uint(id) == c.GetUint("userID") {
+		c.Error(apperror.OwnRoleChange)
+		return
+	}
+
+	var user model.User
+	if err := config.DB.Select("id", "role").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.Error(apperror.UserNotFound)
+			return
+		}
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	// Tidak boleh memberi (atau mencabut) akses yang tidak dimiliki sendiri
+	callerRole := c.GetString("role")
+	if !permission.Covers(callerRole, input.Role) || !permission.Covers(callerRole, user.Role) {
+		c.Error(apperror.RoleEscalation)
+		return
+	}
+
+	res := config.DB.Model(&model.User{}).Where("id = ?", id).Update("role", input.Role)
+	if res.Error != nil {
+		c.Error(apperror.Internal.Wrap(res.Error))
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.Error(apperror.UserNotFound)
+		return
+	}
+
+	// Role ada di dalam token, jadi user harus login ulang
+	if err := auth.RevokeUserSessions(uint(id), 0); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.user_role_changed")})
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	var unknown permission.UnknownPermissionError
+	switch {
+	case errors.As(err, &unknown):
+		c.Error(apperror.UnknownPermission.With("permission", unknown.Name))
+	case errors.Is(err, permission.ErrRoleNotFound):
+		c.Error(apperror.RoleNotFound)
+	case errors.Is(err, permission.ErrRoleBuiltIn):
+		c.Error(apperror.RoleBuiltIn)
+	case errors.Is(err, permission.ErrRoleInUse):
+		c.Error(apperror.RoleInUse)
+	default:
+		c.Error(apperror.Internal.Wrap(err))
+	}
+}
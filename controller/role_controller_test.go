@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+)
+
+// Pemegang roles.manage yang bukan admin tidak boleh membuat atau mengisi
+// role dengan permission yang tidak dimilikinya. Semua kasus ditolak
+// sebelum database disentuh.
+func TestRoleEscalation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), asActor)
+	r.POST("/api/roles", CreateRole)
+	r.PUT("/api/roles/:name/permissions", UpdateRolePermissions)
+
+	cases := []struct {
+		name string
+		role string
+		path string
+		body interface{}
+	}{
+		{"create superset role", "dispatcher", "/api/roles",
+			map[string]interface{}{"name": "super_dispatch", "permissions": []string{"orders.read_all", "roles.manage", "users.manage"}}},
+		{"create role with foreign permission", "finance", "/api/roles",
+			map[string]interface{}{"name": "kasir", "permissions": []string{"orders.delete"}}},
+		{"grant own role more access", "dispatcher", "/api/roles/dispatcher/permissions",
+			map[string]interface{}{"permissions": []string{"orders.read_all", "roles.manage"}}},
+		{"rewrite a role with more access", "dispatcher", "/api/roles/customer/permissions",
+			map[string]interface{}{"permissions": []string{"orders.read_all"}}},
+		{"rewrite admin", "dispatcher", "/api/roles/admin/permissions",
+			map[string]interface{}{"permissions": []string{}}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			json.NewEncoder(&buf).Encode(tc.body)
+			method := http.MethodPost
+			if tc.path != "/api/roles" {
+				method = http.MethodPut
+			}
+			req := httptest.NewRequest(method, tc.path, &buf)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Test-User", "7")
+			req.Header.Set("X-Test-Role", tc.role)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusForbidden || !bytes.Contains(w.Body.Bytes(), []byte("role_escalation")) {
+				t.Fatalf("status %d: %s, want 403 role_escalation", w.Code, w.Body)
+			}
+		})
+	}
+}
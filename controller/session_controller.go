@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+)
+
+// GET /api/sessions — daftar device yang sedang login
+func GetMySessions(c *gin.Context) {
+	sessions, err := auth.ActiveSessions(c.GetUint("userID"))
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSessionViews(sessions, c.GetUint("sessionID")))
+}
+
+// DELETE /api/sessions/:id — logout satu device milik sendiri
+func RevokeMySession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	revokeSession(c, c.GetUint("userID"), uint(sessionID))
+}
+
+// GET /api/users/:id/sessions (admin)
+func GetUserSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	sessions, err := auth.ActiveSessions(uint(userID))
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewSessionViews(sessions, 0))
+}
+
+// DELETE /api/users/:id/sessions/:session_id (admin) — paksa logout satu device
+func RevokeUserSession(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "session_id"))
+		return
+	}
+
+	revokeSession(c, uint(userID), uint(sessionID))
+}
+
+// DELETE /api/users/:id/sessions (admin) — paksa logout semua device
+func RevokeAllUserSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "id"))
+		return
+	}
+
+	if err := auth.RevokeUserSessions(uint(userID), 0); err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.user_sessions_revoked")})
+}
+
+func revokeSession(c *gin.Context, userID, sessionID uint) {
+	err := auth.RevokeUserSession(userID, sessionID)
+	if errors.Is(err, auth.ErrSessionInvalid) {
+		c.Error(apperror.SessionNotFound)
+		return
+	}
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.session_revoked")})
+}
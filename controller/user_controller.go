@@ -1,139 +1,230 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
 	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
 )
 
+// UserHandler endpoint user & kurir; aturan bisnisnya ada di service.UserService
+type UserHandler struct {
+	users *service.UserService
+}
+
+func NewUserHandler(users *service.UserService) *UserHandler {
+	return &UserHandler{users: users}
+}
+
+// respondUserError menerjemahkan error UserService
+func respondUserError(c *gin.Context, err error) {
+	var taken *service.ContactTakenError
+	var weak *utils.PasswordError
+	switch {
+	case errors.As(err, &taken):
+		c.Error(contactTakenError(taken.Field))
+	case errors.As(err, &weak):
+		c.Error(passwordError(err))
+	case errors.Is(err, service.ErrUserNotFound):
+		c.Error(apperror.UserNotFound)
+	case errors.Is(err, service.ErrKurirNotFound):
+		c.Error(apperror.KurirNotFound)
+	case errors.Is(err, service.ErrKurirInactive):
+		c.Error(apperror.KurirInactive)
+	default:
+		c.Error(apperror.Internal.Wrap(err))
+	}
+}
+
+// contactTakenError konflik email / nomor HP, ditempel ke field-nya
+func contactTakenError(field string) *apperror.Error {
+	if field == "phone" {
+		return apperror.PhoneTaken.OnField(field)
+	}
+	return apperror.EmailTaken.OnField(field)
+}
+
 // GET /users
-func GetAllUsers(c *gin.Context) {
-	var users []model.User
-	if err := config.DB.Find(&users).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+func (h *UserHandler) GetAllUsers(c *gin.Context) {
+	users, err := h.users.List(c.Request.Context())
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, dto.NewUserPublics(users))
 }
 
-func GetAvailableKurir(c *gin.Context) {
-	var kurirs []model.User
-
-	// Ambil semua kurir online
-	if err := config.DB.
-		Where("role = ? AND status = ? AND status_kerja = ?", "kurir", "online", "aktif").
-		Find(&kurirs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+func (h *UserHandler) GetAvailableKurir(c *gin.Context) {
+	// Kurir online yang pesanan aktifnya masih di bawah batas
+	kurirs, err := h.users.AvailableKurir(c.Request.Context())
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
+	showContact := policy.ViewKurirContact(policy.ActorFrom(c))
+
 	var filtered []map[string]interface{}
-	for _, kurir := range kurirs {
-		var count int64
-		config.DB.
-			Model(&model.Order{}).
-			Where("kurir_id = ? AND status = ?", kurir.ID, "proses").
-			Count(&count)
-
-		if count < 5 {
-			filtered = append(filtered, map[string]interface{}{
-				"id":             kurir.ID,
-				"name":           kurir.Name,
-				"kendaraan":      kurir.Kendaraan,
-				"jumlah_pesanan": count,
-				"no_hp":          kurir.Phone,
-			})
+	for _, k := range kurirs {
+		item := map[string]interface{}{
+			"id":             k.User.ID,
+			"name":           k.User.Name,
+			"kendaraan":      k.User.Kendaraan,
+			"jumlah_pesanan": k.ActiveOrders,
+		}
+		if showContact {
+			item["no_hp"] = k.User.Phone
 		}
+		filtered = append(filtered, item)
 	}
 
 	c.JSON(http.StatusOK, filtered)
 }
 
-func GetKurirByID(c *gin.Context) {
-	idParam := c.Param("id")
+// CreateKurirRequest dipakai admin untuk mendaftarkan kurir baru
+type CreateKurirRequest struct {
+	Name      string `json:"name" binding:"required,min=2,max=100"`
+	Email     string `json:"email" binding:"required,email,max=100"`
+	Phone     string `json:"phone" binding:"required,phone_id"`
+	Password  string `json:"password" binding:"required"`
+	Kendaraan string `json:"kendaraan" binding:"required,max=50"`
+	PlatNomor string `json:"plat_nomor" binding:"required,max=15"`
+}
 
-	var user model.User
-	if err := config.DB.First(&user, "id = ? AND role = ?", idParam, "kurir").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Kurir tidak ditemukan"})
-		return
+// ProfileRequest perubahan data kontak; field yang tidak dikirim tidak diubah
+type ProfileRequest struct {
+	Name  *string `json:"name" binding:"omitnil,min=2,max=100"`
+	Email *string `json:"email" binding:"omitnil,email,max=100"`
+	Phone *string `json:"phone" binding:"omitnil,phone_id"`
+}
+
+// profile dinormalkan seperti CreateKurir: email huruf kecil, nomor HP 08...
+func (r ProfileRequest) profile() service.Profile {
+	var p service.Profile
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		p.Name = &name
+	}
+	if r.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*r.Email))
+		p.Email = &email
 	}
+	if r.Phone != nil {
+		phone := utils.NormalizePhone(*r.Phone)
+		p.Phone = &phone
+	}
+	return p
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"user": gin.H{
-			"id":         user.ID,
-			"name":       user.Name,
-			"email":      user.Email,
-			"phone":      user.Phone,
-			"kendaraan":  user.Kendaraan,
-			"plat_nomor": user.PlatNomor, // ← tambahkan ini
-			"status":     user.Status,
-		},
-	})
+// KurirProfileRequest ProfileRequest ditambah data kendaraan kurir
+type KurirProfileRequest struct {
+	ProfileRequest
+	Kendaraan *string `json:"kendaraan" binding:"omitnil,max=50"`
+	PlatNomor *string `json:"plat_nomor" binding:"omitnil,max=15"`
 }
 
-// PUT /api/kurir/:id
-func UpdateKurirByID(c *gin.Context) {
-	idParam := c.Param("id")
+func (r KurirProfileRequest) profile() service.Profile {
+	p := r.ProfileRequest.profile()
+	if r.Kendaraan != nil {
+		kendaraan := strings.TrimSpace(*r.Kendaraan)
+		p.Kendaraan = &kendaraan
+	}
+	if r.PlatNomor != nil {
+		platNomor := strings.ToUpper(strings.TrimSpace(*r.PlatNomor))
+		p.PlatNomor = &platNomor
+	}
+	return p
+}
 
-	var user model.User
-	if err := config.DB.First(&user, "id = ? AND role = ?", idParam, "kurir").Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Kurir tidak ditemukan"})
+// POST /api/kurir (admin)
+func (h *UserHandler) CreateKurir(c *gin.Context) {
+	var input CreateKurirRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
 		return
 	}
 
-	var input struct {
-		Name      string  `json:"name"`
-		Phone     string  `json:"phone"`
-		Kendaraan *string `json:"kendaraan"`
-		Email     string  `json:"email"`
-		PlatNomor *string `json:"plat_nomor"`
+	kendaraan := strings.TrimSpace(input.Kendaraan)
+	platNomor := strings.ToUpper(strings.TrimSpace(input.PlatNomor))
+	user := model.User{
+		Name:        strings.TrimSpace(input.Name),
+		Email:       strings.ToLower(strings.TrimSpace(input.Email)),
+		Phone:       utils.NormalizePhone(input.Phone),
+		Role:        "kurir",
+		Kendaraan:   &kendaraan,
+		PlatNomor:   &platNomor,
+		Status:      "offline",
+		StatusKerja: "aktif",
 	}
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+	if err := h.users.Create(c.Request.Context(), &user, input.Password); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	user.Name = input.Name
-	user.Phone = input.Phone
-	user.Kendaraan = input.Kendaraan
-	user.Email = input.Email
-	user.PlatNomor = input.PlatNomor
+	c.JSON(http.StatusCreated, gin.H{"message": i18n.Msg(c, "msg.kurir_created"), "user": dto.NewUserPublic(user)})
+}
 
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update kurir"})
+func (h *UserHandler) GetKurirByID(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Profil kurir berhasil diperbarui"})
+	user, err := h.users.Kurir(c.Request.Context(), id)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	item := gin.H{
+		"id":         user.ID,
+		"name":       user.Name,
+		"kendaraan":  user.Kendaraan,
+		"plat_nomor": user.PlatNomor,
+		"status":     user.Status,
+	}
+	// Kontak kurir hanya untuk kurir itu sendiri dan yang mengatur pengiriman
+	if actor := policy.ActorFrom(c); actor.ID == user.ID || policy.ViewKurirContact(actor) {
+		item["email"] = user.Email
+		item["phone"] = user.Phone
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": item})
 }
 
-func GetUserProfile(c *gin.Context) {
-	userIDInterface, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+// PUT /api/kurir/:id
+func (h *UserHandler) UpdateKurirByID(c *gin.Context) {
+	kurirID, ok := authorizeKurir(c, policy.EditKurirProfile)
+	if !ok {
 		return
 	}
 
-	var userID uint
-	switch id := userIDInterface.(type) {
-	case uint:
-		userID = id
-	case int:
-		userID = uint(id)
-	case float64:
-		userID = uint(id)
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	var input KurirProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
 		return
 	}
 
-	var user model.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	if err := h.users.UpdateKurirProfile(c.Request.Context(), kurirID, input.profile()); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.kurir_profile_updated")})
+}
+
+func (h *UserHandler) GetUserProfile(c *gin.Context) {
+	user, err := h.users.Get(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		respondUserError(c, err)
 		return
 	}
 
@@ -146,129 +237,95 @@ func GetUserProfile(c *gin.Context) {
 	})
 }
 
-func UpdateKurirStatus(c *gin.Context) {
+func (h *UserHandler) UpdateKurirStatus(c *gin.Context) {
 	var input struct {
 		ID     uint   `json:"id"`     // sementara kita pakai ID dari body
 		Status string `json:"status"` // online / offline
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		c.Error(apperror.InvalidRequest)
 		return
 	}
 
-	if err := config.DB.Model(&model.User{}).Where("id = ? AND role = ?", input.ID, "kurir").
-		Update("status", input.Status).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update status"})
+	if err := h.users.SetKurirStatus(c.Request.Context(), input.ID, input.Status); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Status berhasil diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.status_updated")})
 }
 
 // GET /users/:id
-func GetUserByID(c *gin.Context) {
-	id := c.Param("id")
-	var user model.User
-	if err := config.DB.First(&user, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
-		return
-	}
-	c.JSON(http.StatusOK, user)
-}
-
-func UpdateProfile(c *gin.Context) {
-	userIDInterface, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+func (h *UserHandler) GetUserByID(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
 
-	var userID uint
-	switch id := userIDInterface.(type) {
-	case uint:
-		userID = id
-	case int:
-		userID = uint(id)
-	case float64:
-		userID = uint(id)
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	user, err := h.users.Get(c.Request.Context(), id)
+	if err != nil {
+		respondUserError(c, err)
 		return
 	}
+	c.JSON(http.StatusOK, dto.NewUserPublic(user))
+}
 
-	var input struct {
-		Name  string `json:"name"`
-		Phone string `json:"phone"`
-		Email string `json:"email"`
-	}
-
+func (h *UserHandler) UpdateProfile(c *gin.Context) {
+	var input ProfileRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		c.Error(validationError(c, err))
 		return
 	}
 
-	var user model.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	if err := h.users.UpdateProfile(c.Request.Context(), c.GetUint("userID"), input.profile()); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	// Update field
-	user.Name = input.Name
-	user.Phone = input.Phone
-	user.Email = input.Email
-
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal update profil"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Profil berhasil diperbarui"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.profile_updated")})
 }
 
 // PUT /users/:id
-func UpdateUser(c *gin.Context) {
-	id := c.Param("id")
-	var user model.User
-	if err := config.DB.First(&user, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
 
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// Hanya data kontak; role dan status diubah lewat endpoint masing-masing
+	var input ProfileRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.Error(validationError(c, err))
 		return
 	}
 
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// Email / nomor HP yang sudah dipakai user lain dijawab 409, bukan 500
+	if err := h.users.UpdateProfile(c.Request.Context(), id, input.profile()); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
-}
-
-// DELETE /users/:id
-func SoftDeleteUser(c *gin.Context) {
-	id := c.Param("id")
-	userID, err := strconv.Atoi(id)
+	user, err := h.users.Get(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		respondUserError(c, err)
 		return
 	}
 
-	var user model.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+	c.JSON(http.StatusOK, dto.NewUserPublic(user))
+}
+
+// DELETE /users/:id — user dinonaktifkan dan semua sesinya dicabut
+func (h *UserHandler) SoftDeleteUser(c *gin.Context) {
+	id, ok := paramID(c)
+	if !ok {
 		return
 	}
 
-	user.StatusKerja = "nonaktif"
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.users.Deactivate(c.Request.Context(), id); err != nil {
+		respondUserError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Kurir berhasil dinonaktifkan"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.user_deactivated")})
 }
@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+)
+
+// userFixture dua customer dan satu kurir di repository memori; budi sudah
+// memverifikasi nomornya
+type userFixture struct {
+	router     *gin.Engine
+	repos      repository.Repositories
+	budi, siti model.User
+	andi       model.User
+}
+
+func newUserFixture(t *testing.T) *userFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	verified := time.Now()
+	f := &userFixture{repos: repository.NewMemory()}
+	f.budi = model.User{Name: "Budi", Email: "budi@example.com", Phone: "081200000001", Role: "customer", PhoneVerifiedAt: &verified}
+	f.siti = model.User{Name: "Siti", Email: "siti@example.com", Phone: "081200000002", Role: "customer"}
+	kendaraan, platNomor := "Honda Beat", "B 1234 XYZ"
+	f.andi = model.User{Name: "Andi", Email: "andi@example.com", Phone: "081200000003", Role: "kurir",
+		Kendaraan: &kendaraan, PlatNomor: &platNomor, StatusKerja: "aktif"}
+	for _, u := range []*model.User{&f.budi, &f.siti, &f.andi} {
+		if err := f.repos.Users.Save(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := service.New(f.repos, service.PublisherFunc(func(context.Context, string, interface{}) error { return nil }),
+		func(uint, uint) error { return nil })
+	users := NewUserHandler(svc.Users)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), asActor)
+	r.PUT("/api/users/:id", users.UpdateUser)
+	r.PUT("/api/update-profile", users.UpdateProfile)
+	r.PUT("/api/kurir/up/:id", users.UpdateKurirByID)
+	r.GET("/api/kurir/:id", users.GetKurirByID)
+	f.router = r
+	return f
+}
+
+func (f *userFixture) put(as model.User, path, body string) *httptest.ResponseRecorder {
+	return f.do(as, http.MethodPut, path, body)
+}
+
+func (f *userFixture) do(as model.User, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Test-User", fmt.Sprint(as.ID))
+	req.Header.Set("X-Test-Role", as.Role)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f *userFixture) reload(t *testing.T, id uint) model.User {
+	t.Helper()
+	user, err := f.repos.Users.FindByID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+var admin = model.User{Role: "admin"}
+
+// Body sebagian hanya mengubah field yang dikirim
+func TestUpdateUserPartialBody(t *testing.T) {
+	f := newUserFixture(t)
+	path := fmt.Sprintf("/api/users/%d", f.budi.ID)
+
+	if w := f.put(admin, path, `{"name":"Budi Santoso"}`); w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	got := f.reload(t, f.budi.ID)
+	if got.Name != "Budi Santoso" || got.Email != f.budi.Email || got.Phone != f.budi.Phone {
+		t.Fatalf("after name-only update: name %q email %q phone %q", got.Name, got.Email, got.Phone)
+	}
+	if got.PhoneVerifiedAt == nil {
+		t.Fatal("phone verification cleared although phone was not sent")
+	}
+
+	if w := f.put(admin, path, `{"phone":"+62 812-0000-0009"}`); w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	got = f.reload(t, f.budi.ID)
+	if got.Phone != "081200000009" || got.Name != "Budi Santoso" || got.Email != f.budi.Email {
+		t.Fatalf("after phone-only update: name %q email %q phone %q", got.Name, got.Email, got.Phone)
+	}
+	if got.PhoneVerifiedAt != nil {
+		t.Fatal("phone verification kept after the phone changed")
+	}
+}
+
+func TestUpdateUserValidation(t *testing.T) {
+	f := newUserFixture(t)
+	path := fmt.Sprintf("/api/users/%d", f.budi.ID)
+
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid email", `{"email":"bukan-email"}`, http.StatusBadRequest},
+		{"invalid phone", `{"phone":"12345"}`, http.StatusBadRequest},
+		{"name too short", `{"name":"B"}`, http.StatusBadRequest},
+		{"email taken, different case", `{"email":"SITI@Example.com"}`, http.StatusConflict},
+		{"phone taken, different format", `{"phone":"+6281200000002"}`, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := f.put(admin, path, tc.body); w.Code != tc.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+
+	if got := f.reload(t, f.budi.ID); got.Email != f.budi.Email || got.Phone != f.budi.Phone || got.Name != f.budi.Name {
+		t.Fatalf("rejected updates changed the user: %+v", got)
+	}
+}
+
+func TestUpdateUserLowercasesEmail(t *testing.T) {
+	f := newUserFixture(t)
+
+	w := f.put(admin, fmt.Sprintf("/api/users/%d", f.budi.ID), `{"email":"Budi.Baru@Example.COM"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var body struct {
+		Email string `json:"email"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Email != "budi.baru@example.com" {
+		t.Fatalf("response email %q, want lowercased", body.Email)
+	}
+	if got := f.reload(t, f.budi.ID); got.Email != "budi.baru@example.com" {
+		t.Fatalf("stored email %q, want lowercased", got.Email)
+	}
+}
+
+// Profil sendiri dan profil kurir memakai validasi dan normalisasi yang sama
+func TestProfileWritePaths(t *testing.T) {
+	cases := []struct {
+		name       string
+		as, target func(f *userFixture) model.User
+		path       func(f *userFixture) string
+	}{
+		{"update-profile",
+			func(f *userFixture) model.User { return f.budi }, func(f *userFixture) model.User { return f.budi },
+			func(*userFixture) string { return "/api/update-profile" }},
+		{"kurir by admin",
+			func(*userFixture) model.User { return admin }, func(f *userFixture) model.User { return f.andi },
+			func(f *userFixture) string { return fmt.Sprintf("/api/kurir/up/%d", f.andi.ID) }},
+		{"kurir own profile",
+			func(f *userFixture) model.User { return f.andi }, func(f *userFixture) model.User { return f.andi },
+			func(f *userFixture) string { return fmt.Sprintf("/api/kurir/up/%d", f.andi.ID) }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newUserFixture(t)
+			as, path := tc.as(f), tc.path(f)
+			id := tc.target(f).ID
+			before := f.reload(t, id)
+
+			for body, want := range map[string]int{
+				`{"email":"bukan-email"}`:      http.StatusBadRequest,
+				`{"phone":"12345"}`:            http.StatusBadRequest,
+				`{"email":"SITI@example.com"}`: http.StatusConflict,
+			} {
+				if w := f.put(as, path, body); w.Code != want {
+					t.Fatalf("%s: status %d, want %d: %s", body, w.Code, want, w.Body)
+				}
+			}
+
+			if w := f.put(as, path, `{"email":"Baru@Example.COM"}`); w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+			got := f.reload(t, id)
+			if got.Email != "baru@example.com" {
+				t.Fatalf("email %q, want lowercased", got.Email)
+			}
+			if got.Name != before.Name || got.Phone != before.Phone {
+				t.Fatalf("email-only update changed name %q phone %q", got.Name, got.Phone)
+			}
+			if before.Kendaraan != nil && (*got.Kendaraan != *before.Kendaraan || *got.PlatNomor != *before.PlatNomor) {
+				t.Fatalf("email-only update changed kendaraan %q plat %q", *got.Kendaraan, *got.PlatNomor)
+			}
+		})
+	}
+}
+
+func TestUpdateKurirNormalizesPlatNomor(t *testing.T) {
+	f := newUserFixture(t)
+
+	w := f.put(f.andi, fmt.Sprintf("/api/kurir/up/%d", f.andi.ID), `{"plat_nomor":" b 9999 abc "}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if got := f.reload(t, f.andi.ID); *got.PlatNomor != "B 9999 ABC" || *got.Kendaraan != "Honda Beat" {
+		t.Fatalf("kendaraan %q plat %q", *got.Kendaraan, *got.PlatNomor)
+	}
+}
+
+func TestKurirContactVisibility(t *testing.T) {
+	f := newUserFixture(t)
+	path := fmt.Sprintf("/api/kurir/%d", f.andi.ID)
+
+	cases := []struct {
+		name        string
+		as          model.User
+		seesContact bool
+	}{
+		{"customer", f.budi, false},
+		{"other kurir", model.User{ID: 99, Role: "kurir"}, false},
+		{"kurir itself", f.andi, true},
+		{"admin", admin, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := f.do(tc.as, http.MethodGet, path, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", w.Code, w.Body)
+			}
+			var body struct {
+				User map[string]interface{} `json:"user"`
+			}
+			json.NewDecoder(w.Body).Decode(&body)
+			_, email := body.User["email"]
+			_, phone := body.User["phone"]
+			if email != tc.seesContact || phone != tc.seesContact {
+				t.Fatalf("email shown %v phone shown %v, want %v", email, phone, tc.seesContact)
+			}
+			if body.User["name"] != f.andi.Name {
+				t.Fatalf("name %v, want %q", body.User["name"], f.andi.Name)
+			}
+		})
+	}
+}
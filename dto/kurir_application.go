@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// KurirDocumentView tidak pernah membuka path file di server,
+// berkas diambil lewat endpoint admin
+type KurirDocumentView struct {
+	ID          uint       `json:"id"`
+	Type        string     `json:"type"`
+	ContentType string     `json:"content_type"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	FileURL     string     `json:"file_url"`
+	UploadedAt  time.Time  `json:"uploaded_at"`
+}
+
+type KurirApplicationView struct {
+	ID           uint                `json:"id"`
+	Status       string              `json:"status"`
+	RejectReason *string             `json:"reject_reason"`
+	ReviewedBy   *uint               `json:"reviewed_by"`
+	ReviewedAt   *time.Time          `json:"reviewed_at"`
+	CreatedAt    time.Time           `json:"created_at"`
+	Kurir        UserPublic          `json:"kurir"`
+	Documents    []KurirDocumentView `json:"documents,omitempty"`
+}
+
+func NewKurirDocumentView(d model.KurirDocument) KurirDocumentView {
+	return KurirDocumentView{
+		ID:          d.ID,
+		Type:        d.Type,
+		ContentType: d.ContentType,
+		ExpiresAt:   d.ExpiresAt,
+		FileURL:     fmt.Sprintf("/api/kurir/documents/%d/file", d.ID),
+		UploadedAt:  d.CreatedAt,
+	}
+}
+
+// NewKurirApplicationView menyertakan dokumen kalau diberikan (untuk halaman detail)
+func NewKurirApplicationView(a model.KurirApplication, docs []model.KurirDocument) KurirApplicationView {
+	v := KurirApplicationView{
+		ID:           a.ID,
+		Status:       a.Status,
+		RejectReason: a.RejectReason,
+		ReviewedBy:   a.ReviewedBy,
+		ReviewedAt:   a.ReviewedAt,
+		CreatedAt:    a.CreatedAt,
+		Kurir:        NewUserPublic(a.User),
+	}
+	for _, d := range docs {
+		v.Documents = append(v.Documents, NewKurirDocumentView(d))
+	}
+	return v
+}
+
+func NewKurirApplicationViews(apps []model.KurirApplication) []KurirApplicationView {
+	out := make([]KurirApplicationView, 0, len(apps))
+	for _, a := range apps {
+		out = append(out, NewKurirApplicationView(a, nil))
+	}
+	return out
+}
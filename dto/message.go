@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// MessageView adalah bentuk pesan chat untuk client dan publish Centrifugo
+type MessageView struct {
+	ID         uint      `json:"id"`
+	OrderID    uint      `json:"order_id"`
+	SenderID   uint      `json:"sender_id"`
+	ReceiverID uint      `json:"receiver_id"`
+	Content    string    `json:"content"`
+	SentAt     time.Time `json:"sent_at"`
+	IsRead     bool      `json:"is_read"`
+
+	// Key "Sender" dipertahankan agar client lama tetap jalan
+	Sender *UserBrief `json:"Sender,omitempty"`
+}
+
+// NewMessageView hanya menyertakan Sender kalau sudah di-preload
+func NewMessageView(m model.Message) MessageView {
+	v := MessageView{
+		ID:         m.ID,
+		OrderID:    m.OrderID,
+		SenderID:   m.SenderID,
+		ReceiverID: m.ReceiverID,
+		Content:    m.Content,
+		SentAt:     m.SentAt,
+		IsRead:     m.IsRead,
+	}
+	if m.Sender.ID != 0 {
+		sender := NewUserBrief(m.Sender)
+		v.Sender = &sender
+	}
+	return v
+}
+
+func NewMessageViews(messages []model.Message) []MessageView {
+	out := make([]MessageView, 0, len(messages))
+	for _, m := range messages {
+		out = append(out, NewMessageView(m))
+	}
+	return out
+}
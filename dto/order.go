@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// OrderSummary dipakai untuk list order (admin, customer, kurir)
+type OrderSummary struct {
+	ID            uint        `json:"id"`
+	CustomerID    uint        `json:"customer_id"`
+	Customer      *UserPublic `json:"customer,omitempty"`
+	KurirID       uint        `json:"kurir_id"`
+	Kurir         *UserPublic `json:"kurir,omitempty"`
+	Layanan       string      `json:"layanan"`
+	Status        string      `json:"status"`
+	Nominal       *uint       `json:"nominal"`
+	PaymentStatus *string     `json:"payment_status"`
+	MetodeBayar   string      `json:"metode_bayar"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// NewOrderSummary hanya menyertakan customer/kurir kalau sudah di-preload
+func NewOrderSummary(o model.Order) OrderSummary {
+	s := OrderSummary{
+		ID:            o.ID,
+		CustomerID:    o.CustomerID,
+		KurirID:       o.KurirID,
+		Layanan:       o.Layanan,
+		Status:        o.Status,
+		Nominal:       o.Nominal,
+		PaymentStatus: o.PaymentStatus,
+		MetodeBayar:   o.MetodeBayar,
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
+	}
+	if o.Customer.ID != 0 {
+		customer := NewUserPublic(o.Customer)
+		s.Customer = &customer
+	}
+	if o.Kurir.ID != 0 {
+		kurir := NewUserPublic(o.Kurir)
+		s.Kurir = &kurir
+	}
+	return s
+}
+
+func NewOrderSummaries(orders []model.Order) []OrderSummary {
+	out := make([]OrderSummary, 0, len(orders))
+	for _, o := range orders {
+		out = append(out, NewOrderSummary(o))
+	}
+	return out
+}
+
+// OrderDetail dipakai untuk satu order, customer & kurir selalu disertakan
+type OrderDetail struct {
+	ID            uint       `json:"id"`
+	CustomerID    uint       `json:"customer_id"`
+	Customer      UserPublic `json:"customer"`
+	KurirID       uint       `json:"kurir_id"`
+	Kurir         UserPublic `json:"kurir"`
+	Layanan       string     `json:"layanan"`
+	Status        string     `json:"status"`
+	Nominal       *uint      `json:"nominal"`
+	PaymentStatus *string    `json:"payment_status"`
+	MetodeBayar   string     `json:"metode_bayar"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func NewOrderDetail(o model.Order) OrderDetail {
+	return OrderDetail{
+		ID:            o.ID,
+		CustomerID:    o.CustomerID,
+		Customer:      NewUserPublic(o.Customer),
+		KurirID:       o.KurirID,
+		Kurir:         NewUserPublic(o.Kurir),
+		Layanan:       o.Layanan,
+		Status:        o.Status,
+		Nominal:       o.Nominal,
+		PaymentStatus: o.PaymentStatus,
+		MetodeBayar:   o.MetodeBayar,
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package dto
+
+// Pagination dikirim bersama list yang dipaging
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int64 `json:"total_pages"`
+}
+
+func NewPagination(page, limit int, total int64) Pagination {
+	totalPages := int64(0)
+	if limit > 0 {
+		totalPages = (total + int64(limit) - 1) / int64(limit)
+	}
+	return Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages}
+}
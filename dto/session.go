@@ -0,0 +1,37 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// SessionView adalah satu device yang sedang login
+type SessionView struct {
+	ID         uint      `json:"id"`
+	DeviceName string    `json:"device_name"`
+	Platform   string    `json:"platform"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+// NewSessionViews menandai session yang sedang dipakai request ini
+func NewSessionViews(sessions []model.Session, currentID uint) []SessionView {
+	out := make([]SessionView, 0, len(sessions))
+	for _, s := range sessions {
+		out = append(out, SessionView{
+			ID:         s.ID,
+			DeviceName: s.DeviceName,
+			Platform:   s.Platform,
+			IP:         s.IP,
+			UserAgent:  s.UserAgent,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			Current:    s.ID == currentID,
+		})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "github.com/mubarok-ridho/misi-paket.backend/model"
+
+// UserPublic adalah bentuk user yang aman dikirim ke client (tanpa password)
+type UserPublic struct {
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	Email         string  `json:"email"`
+	Role          string  `json:"role"`
+	Phone         string  `json:"phone"`
+	Kendaraan     *string `json:"kendaraan,omitempty"`
+	PlatNomor     *string `json:"plat_nomor,omitempty"`
+	Status        string  `json:"status"`
+	StatusKerja   string  `json:"status_kerja"`
+	PhoneVerified bool    `json:"phone_verified"`
+}
+
+func NewUserPublic(u model.User) UserPublic {
+	return UserPublic{
+		ID:            u.ID,
+		Name:          u.Name,
+		Email:         u.Email,
+		Role:          u.Role,
+		Phone:         u.Phone,
+		Kendaraan:     u.Kendaraan,
+		PlatNomor:     u.PlatNomor,
+		Status:        u.Status,
+		StatusKerja:   u.StatusKerja,
+		PhoneVerified: u.PhoneVerifiedAt != nil,
+	}
+}
+
+// UserBrief dipakai saat cukup menampilkan identitas singkat (mis. pengirim chat)
+type UserBrief struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+func NewUserBrief(u model.User) UserBrief {
+	return UserBrief{ID: u.ID, Name: u.Name, Role: u.Role}
+}
+
+func NewUserPublics(users []model.User) []UserPublic {
+	out := make([]UserPublic, 0, len(users))
+	for _, u := range users {
+		out = append(out, NewUserPublic(u))
+	}
+	return out
+}
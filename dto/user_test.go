@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+const testHash = "$2a$10$abcdefghijklmnopqrstuuvwxyz0123456789ABCDEFGHIJKLMNOP"
+
+func testUser(id uint, role string) model.User {
+	now := time.Now()
+	kendaraan, plat := "Motor", "B 1234 XY"
+	return model.User{
+		ID:              id,
+		Name:            "User " + role,
+		Email:           role + "@example.com",
+		Password:        testHash,
+		Role:            role,
+		Phone:           "081234567890",
+		Kendaraan:       &kendaraan,
+		PlatNomor:       &plat,
+		Status:          "online",
+		StatusKerja:     "aktif",
+		PhoneVerifiedAt: &now,
+	}
+}
+
+// Semua DTO yang berisi user tidak boleh membawa password maupun hash-nya
+func TestDTOsNeverExposePassword(t *testing.T) {
+	customer, kurir := testUser(1, "customer"), testUser(2, "kurir")
+	order := model.Order{ID: 10, CustomerID: customer.ID, Customer: customer, KurirID: kurir.ID, Kurir: kurir, Layanan: "antar barang"}
+	message := model.Message{ID: 5, OrderID: order.ID, SenderID: customer.ID, Sender: customer, ReceiverID: kurir.ID, Content: "halo"}
+	application := model.KurirApplication{ID: 3, UserID: kurir.ID, User: kurir, Status: "pending"}
+
+	cases := []struct {
+		name string
+		dto  interface{}
+	}{
+		{"UserPublic", NewUserPublic(customer)},
+		{"UserPublics", NewUserPublics([]model.User{customer, kurir})},
+		{"UserBrief", NewUserBrief(kurir)},
+		{"OrderSummary", NewOrderSummary(order)},
+		{"OrderDetail", NewOrderDetail(order)},
+		{"MessageView", NewMessageView(message)},
+		{"KurirApplicationView", NewKurirApplicationView(application, nil)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(tc.dto)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if strings.Contains(string(raw), testHash) {
+				t.Fatalf("hash password ikut terkirim: %s", raw)
+			}
+
+			var decoded interface{}
+			if err := json.Unmarshal(raw, &decoded); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if path, ok := findKey(decoded, "password", tc.name); ok {
+				t.Fatalf("key password ditemukan di %s: %s", path, raw)
+			}
+		})
+	}
+}
+
+// findKey mencari key (tanpa beda huruf besar/kecil) di seluruh isi JSON
+func findKey(v interface{}, key, path string) (string, bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if strings.EqualFold(k, key) {
+				return path + "." + k, true
+			}
+			if p, ok := findKey(child, key, path+"."+k); ok {
+				return p, true
+			}
+		}
+	case []interface{}:
+		for _, child := range val {
+			if p, ok := findKey(child, key, path+"[]"); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
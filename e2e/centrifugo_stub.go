@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+)
+
+// Publication satu panggilan publish yang diterima stub
+type Publication struct {
+	Channel string
+	Data    json.RawMessage
+}
+
+// StubCentrifugo meniru server API Centrifugo: menerima publish, mencatatnya,
+// dan bisa diminta gagal untuk menguji jalur error
+type StubCentrifugo struct {
+	*httptest.Server
+	APIKey string
+
+	mu         sync.Mutex
+	published  []Publication
+	failStatus int
+}
+
+func NewStubCentrifugo(apiKey string) *StubCentrifugo {
+	s := &StubCentrifugo{APIKey: apiKey}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// APIURL dipakai sebagai CENTRIFUGO_API_URL
+func (s *StubCentrifugo) APIURL() string {
+	return s.URL + "/api"
+}
+
+func (s *StubCentrifugo) serve(w http.ResponseWriter, r *http.Request) {
+	// GET dijawab 405 seperti Centrifugo asli; readiness menganggapnya hidup
+	if r.Method != http.MethodPost || r.URL.Path != "/api" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("X-API-Key") != s.APIKey {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	fail := s.failStatus
+	s.mu.Unlock()
+	if fail != 0 {
+		http.Error(w, "stub failure", fail)
+		return
+	}
+
+	var req struct {
+		Method string `json:"method"`
+		Params struct {
+			Channel string          `json:"channel"`
+			Data    json.RawMessage `json:"data"`
+		} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "publish" || req.Params.Channel == "" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.published = append(s.published, Publication{Channel: req.Params.Channel, Data: req.Params.Data})
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"result":{}}`))
+}
+
+// Published semua publish ke channel ini, urut waktu diterima
+func (s *StubCentrifugo) Published(channel string) []Publication {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Publication
+	for _, p := range s.published {
+		if p.Channel == channel {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// FailWith membuat publish berikutnya dijawab dengan status ini; 0 untuk normal lagi
+func (s *StubCentrifugo) FailWith(status int) {
+	s.mu.Lock()
+	s.failStatus = status
+	s.mu.Unlock()
+}
@@ -0,0 +1,419 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Setiap test boleh membuat pesanannya sendiri tapi tidak boleh bergantung
+// pada sisa data test lain.
+
+// step menjalankan satu langkah flow; langkah yang gagal menghentikan flow
+func step(t *testing.T, name string, fn func(t *testing.T)) {
+	t.Helper()
+	if !t.Run(name, fn) {
+		t.FailNow()
+	}
+}
+
+// expect memastikan status sesuai; body ikut di pesan supaya mudah dilacak
+func expect(t *testing.T, res Response, status int) {
+	t.Helper()
+	if err := res.Expect(status); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func decode(t *testing.T, res Response, v interface{}) {
+	t.Helper()
+	if err := res.Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createOrder membuat pesanan customer untuk kurir hasil seed
+func createOrder(t *testing.T, h *Harness) uint {
+	t.Helper()
+	res := h.Do(http.MethodPost, "/api/orders", h.Accounts["customer"], map[string]interface{}{
+		"kurir_id": h.Accounts["kurir"].User.ID,
+		"layanan":  "antar barang",
+	})
+	expect(t, res, http.StatusCreated)
+
+	var body struct {
+		OrderID uint `json:"order_id"`
+	}
+	decode(t, res, &body)
+	if body.OrderID == 0 {
+		t.Fatalf("order_id kosong: %s", res.Body)
+	}
+	return body.OrderID
+}
+
+func TestRegisterLogin(t *testing.T) {
+	h := harness(t)
+	email := fmt.Sprintf("baru%d@e2e.test", time.Now().UnixNano())
+	var account *Account
+
+	step(t, "register", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPost, "/register", nil, map[string]string{
+			"name":     "Customer Baru",
+			"email":    email,
+			"phone":    "081299990001",
+			"password": SeedPassword,
+		}), http.StatusCreated)
+	})
+
+	step(t, "login password salah", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPost, "/login", nil, map[string]string{"email": email, "password": "salah-password1"}),
+			http.StatusUnauthorized)
+	})
+
+	step(t, "login", func(t *testing.T) {
+		res := h.Do(http.MethodPost, "/login", nil, map[string]string{"email": email, "password": SeedPassword})
+		expect(t, res, http.StatusOK)
+		var login struct {
+			Token string `json:"token"`
+			User  struct {
+				Role string `json:"role"`
+			} `json:"user"`
+		}
+		decode(t, res, &login)
+		if login.Token == "" || login.User.Role != "customer" {
+			t.Fatalf("token/role tidak sesuai: %s", res.Body)
+		}
+		account = &Account{Token: login.Token}
+	})
+
+	step(t, "profil", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, "/api/users/profile", account, nil), http.StatusOK)
+	})
+
+	// Customer baru tidak boleh membuka endpoint admin
+	step(t, "akses admin", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, "/api/users", account, nil), http.StatusForbidden)
+	})
+
+	step(t, "logout", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPost, "/api/logout", account, nil), http.StatusOK)
+	})
+
+	step(t, "token setelah logout", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, "/api/users/profile", account, nil), http.StatusUnauthorized)
+	})
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	h := harness(t)
+	customer, kurir := h.Accounts["customer"], h.Accounts["kurir"]
+	dispatcher, outsider := h.Accounts["dispatcher"], h.Accounts["outsider"]
+
+	orderID := createOrder(t, h)
+	orderPath := fmt.Sprintf("/api/orders/%d", orderID)
+
+	step(t, "customer melihat pesanan", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, orderPath, customer, nil), http.StatusOK)
+	})
+	step(t, "customer lain melihat pesanan", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, orderPath, outsider, nil), http.StatusForbidden)
+	})
+
+	step(t, "daftar pesanan proses kurir", func(t *testing.T) {
+		res := h.Do(http.MethodGet, fmt.Sprintf("/api/kurir/%d/orders/proses", kurir.User.ID), kurir, nil)
+		expect(t, res, http.StatusOK)
+		var proses []struct {
+			ID uint `json:"id"`
+		}
+		decode(t, res, &proses)
+		if !containsID(len(proses), func(i int) uint { return proses[i].ID }, orderID) {
+			t.Fatalf("pesanan %d tidak ada di daftar proses kurir: %s", orderID, res.Body)
+		}
+	})
+
+	// Kurir offline dulu supaya terlihat kembali online setelah pesanan selesai
+	step(t, "kurir offline", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, "/api/kurir/status", dispatcher, map[string]interface{}{"id": kurir.User.ID, "status": "offline"}),
+			http.StatusOK)
+	})
+
+	// Customer tidak boleh menyelesaikan pesanannya sendiri
+	step(t, "customer mengubah status", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, orderPath, customer, map[string]string{"status": "selesai"}), http.StatusForbidden)
+	})
+
+	step(t, "kurir menyelesaikan pesanan", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, orderPath, kurir, map[string]string{"status": "selesai"}), http.StatusOK)
+	})
+
+	step(t, "pesanan saya", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/api/my-orders", customer, nil)
+		expect(t, res, http.StatusOK)
+		var mine []struct {
+			ID     uint   `json:"id"`
+			Status string `json:"status"`
+		}
+		decode(t, res, &mine)
+		found := false
+		for _, o := range mine {
+			if o.ID == orderID {
+				found = o.Status == "selesai"
+			}
+		}
+		if !found {
+			t.Fatalf("pesanan %d belum selesai di daftar customer: %s", orderID, res.Body)
+		}
+	})
+
+	step(t, "kurir tersedia", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/kurir/available", dispatcher, nil)
+		expect(t, res, http.StatusOK)
+		var available []struct {
+			ID uint `json:"id"`
+		}
+		decode(t, res, &available)
+		if !containsID(len(available), func(i int) uint { return available[i].ID }, kurir.User.ID) {
+			t.Fatalf("kurir tidak kembali online setelah pesanan selesai: %s", res.Body)
+		}
+	})
+
+	step(t, "total selesai hari ini", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/api/orders/total-selesai-today", h.Accounts["finance"], nil)
+		expect(t, res, http.StatusOK)
+		var total struct {
+			Total int64 `json:"total_orders_selesai"`
+		}
+		decode(t, res, &total)
+		if total.Total < 1 {
+			t.Fatal("total selesai hari ini masih 0")
+		}
+	})
+}
+
+func TestBillingPayment(t *testing.T) {
+	h := harness(t)
+	customer, kurir, finance := h.Accounts["customer"], h.Accounts["kurir"], h.Accounts["finance"]
+
+	orderID := createOrder(t, h)
+	statusPath := fmt.Sprintf("/orders/%d/status", orderID)
+
+	// Customer tidak punya permission menagih
+	step(t, "customer menagih", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, "/api/orders/tagihan", customer, map[string]interface{}{"id": orderID, "nominal": 1}),
+			http.StatusForbidden)
+	})
+
+	step(t, "kurir menagih", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, "/api/orders/tagihan", kurir, map[string]interface{}{
+			"id":      orderID,
+			"nominal": 25000,
+			"rincian": []map[string]interface{}{{"judul": "Ongkir", "nominal": 25000}},
+		}), http.StatusOK)
+	})
+
+	step(t, "status sebelum pilih metode", func(t *testing.T) {
+		ready := paymentReady(t, h, statusPath)
+		if !ready.TagihanSiap || ready.BisaLanjut {
+			t.Fatalf("status tidak sesuai: %+v", ready)
+		}
+	})
+
+	step(t, "pilih metode bayar", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, fmt.Sprintf("/api/orders/%d/metode_bayar", orderID), customer, map[string]string{"metode_bayar": "cash"}),
+			http.StatusOK)
+	})
+
+	step(t, "status setelah pilih metode", func(t *testing.T) {
+		if ready := paymentReady(t, h, statusPath); !ready.BisaLanjut {
+			t.Fatalf("pesanan belum bisa lanjut: %+v", ready)
+		}
+	})
+
+	step(t, "customer memvalidasi", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, "/api/orders/payment-validasi", customer, map[string]interface{}{"id": orderID}),
+			http.StatusForbidden)
+	})
+	step(t, "finance memvalidasi", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPut, "/api/orders/payment-validasi", finance, map[string]interface{}{"id": orderID}),
+			http.StatusOK)
+	})
+
+	step(t, "detail pesanan", func(t *testing.T) {
+		res := h.Do(http.MethodGet, fmt.Sprintf("/api/orders/%d", orderID), customer, nil)
+		expect(t, res, http.StatusOK)
+		var detail struct {
+			PaymentStatus *string `json:"payment_status"`
+			Tagihan       *uint   `json:"tagihan"`
+		}
+		decode(t, res, &detail)
+		if detail.PaymentStatus == nil || *detail.PaymentStatus != "done" || detail.Tagihan == nil || *detail.Tagihan != 25000 {
+			t.Fatalf("pembayaran tidak tercatat: %s", res.Body)
+		}
+	})
+}
+
+type paymentStatus struct {
+	TagihanSiap bool `json:"tagihan_siap"`
+	MetodeDiisi bool `json:"metode_bayar_diisi"`
+	BisaLanjut  bool `json:"bisa_lanjut"`
+}
+
+func paymentReady(t *testing.T, h *Harness, path string) paymentStatus {
+	t.Helper()
+	var status paymentStatus
+	res := h.Do(http.MethodGet, path, h.Accounts["kurir"], nil)
+	expect(t, res, http.StatusOK)
+	decode(t, res, &status)
+	return status
+}
+
+func TestChat(t *testing.T) {
+	h := harness(t)
+	customer, kurir, outsider := h.Accounts["customer"], h.Accounts["kurir"], h.Accounts["outsider"]
+
+	orderID := createOrder(t, h)
+	channel := fmt.Sprintf("chat:%d", orderID)
+	loadPath := fmt.Sprintf("/chat/load/%d", orderID)
+	send := func(as *Account, text string) Response {
+		return h.Do(http.MethodPost, "/chat/send", as, map[string]string{
+			"order_id": fmt.Sprint(orderID),
+			"message":  text,
+		})
+	}
+	type history struct {
+		Messages []struct {
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+
+	step(t, "customer kirim chat", func(t *testing.T) {
+		expect(t, send(customer, "Halo kak, sudah di mana?"), http.StatusOK)
+
+		published := h.Centrifugo.Published(channel)
+		if len(published) != 1 {
+			t.Fatalf("publish ke %s: %d kali, diharapkan 1", channel, len(published))
+		}
+		var msg struct {
+			SenderID   uint   `json:"sender_id"`
+			ReceiverID uint   `json:"receiver_id"`
+			Content    string `json:"content"`
+		}
+		if err := json.Unmarshal(published[0].Data, &msg); err != nil {
+			t.Fatalf("data publish bukan pesan: %s", published[0].Data)
+		}
+		if msg.SenderID != customer.User.ID || msg.ReceiverID != kurir.User.ID || msg.Content != "Halo kak, sudah di mana?" {
+			t.Fatalf("isi publish tidak sesuai: %s", published[0].Data)
+		}
+	})
+
+	step(t, "customer lain kirim chat", func(t *testing.T) {
+		expect(t, send(outsider, "ikut nimbrung"), http.StatusForbidden)
+	})
+
+	// Centrifugo bermasalah: pesan tetap tersimpan, client dapat 502
+	step(t, "chat saat Centrifugo gagal", func(t *testing.T) {
+		h.Centrifugo.FailWith(http.StatusInternalServerError)
+		res := send(kurir, "OTW kak")
+		h.Centrifugo.FailWith(0)
+		expect(t, res, http.StatusBadGateway)
+	})
+
+	step(t, "riwayat chat", func(t *testing.T) {
+		res := h.Do(http.MethodGet, loadPath, kurir, nil)
+		expect(t, res, http.StatusOK)
+		var got history
+		decode(t, res, &got)
+		if len(got.Messages) != 2 || got.Messages[1].Content != "OTW kak" {
+			t.Fatalf("riwayat chat tidak sesuai: %s", res.Body)
+		}
+	})
+
+	step(t, "customer lain membaca chat", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, loadPath, outsider, nil), http.StatusForbidden)
+	})
+
+	step(t, "hapus chat", func(t *testing.T) {
+		expect(t, h.Do(http.MethodDelete, fmt.Sprintf("/messages/order/%d", orderID), h.Accounts["admin"], nil), http.StatusOK)
+
+		res := h.Do(http.MethodGet, loadPath, customer, nil)
+		var got history
+		decode(t, res, &got)
+		if len(got.Messages) != 0 {
+			t.Fatalf("chat belum terhapus: %s", res.Body)
+		}
+	})
+}
+
+func TestTracking(t *testing.T) {
+	h := harness(t)
+	customer, kurir, outsider := h.Accounts["customer"], h.Accounts["kurir"], h.Accounts["outsider"]
+	trackPath := fmt.Sprintf("/kurir/track/%d", kurir.User.ID)
+
+	orderID := createOrder(t, h)
+
+	step(t, "customer mengirim lokasi", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPost, "/kurir/track", customer, map[string]float64{"lat": 1, "lng": 1}), http.StatusForbidden)
+	})
+	step(t, "kurir mengirim lokasi", func(t *testing.T) {
+		expect(t, h.Do(http.MethodPost, "/kurir/track", kurir, map[string]float64{"lat": -6.2, "lng": 106.8}), http.StatusOK)
+	})
+
+	step(t, "customer melihat lokasi", func(t *testing.T) {
+		res := h.Do(http.MethodGet, trackPath, customer, nil)
+		expect(t, res, http.StatusOK)
+		var loc struct {
+			Lat float64 `json:"lat"`
+			Lng float64 `json:"lng"`
+		}
+		decode(t, res, &loc)
+		if loc.Lat != -6.2 || loc.Lng != 106.8 {
+			t.Fatalf("lokasi tidak sesuai: %s", res.Body)
+		}
+	})
+
+	step(t, "customer lain melihat lokasi", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, trackPath, outsider, nil), http.StatusForbidden)
+	})
+
+	// Setelah semua pesanan customer ini selesai, lokasi kurir tertutup lagi
+	step(t, "selesaikan pesanan aktif", func(t *testing.T) {
+		finishActiveOrders(t, h, orderID)
+	})
+	step(t, "lokasi setelah pesanan selesai", func(t *testing.T) {
+		expect(t, h.Do(http.MethodGet, trackPath, customer, nil), http.StatusForbidden)
+	})
+}
+
+// finishActiveOrders menyelesaikan semua pesanan proses kurir seed,
+// termasuk sisa test lain, supaya customer tidak lagi punya pengantaran aktif
+func finishActiveOrders(t *testing.T, h *Harness, orderID uint) {
+	t.Helper()
+	kurir := h.Accounts["kurir"]
+	res := h.Do(http.MethodGet, fmt.Sprintf("/api/kurir/%d/orders/proses", kurir.User.ID), kurir, nil)
+	expect(t, res, http.StatusOK)
+	var active []struct {
+		ID uint `json:"id"`
+	}
+	decode(t, res, &active)
+	if !containsID(len(active), func(i int) uint { return active[i].ID }, orderID) {
+		t.Fatalf("pesanan %d tidak ada di daftar proses kurir", orderID)
+	}
+
+	for _, o := range active {
+		res := h.Do(http.MethodPut, fmt.Sprintf("/api/orders/%d", o.ID), kurir, map[string]string{"status": "selesai"})
+		if err := res.Expect(http.StatusOK); err != nil {
+			t.Fatalf("selesaikan pesanan %d: %v", o.ID, err)
+		}
+	}
+}
+
+func containsID(n int, at func(int) uint, id uint) bool {
+	for i := 0; i < n; i++ {
+		if at(i) == id {
+			return true
+		}
+	}
+	return false
+}
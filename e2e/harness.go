@@ -0,0 +1,255 @@
+// Package e2e menjalankan router asli (route.SetupRoutes) terhadap database
+// Postgres sementara dan stub Centrifugo. Database dibuat dengan nama unik
+// di server yang diberikan, dimigrasi, di-seed, lalu di-drop saat Close.
+//
+// Karena memakai config global (config.DB, kunci JWT, setting Centrifugo),
+// hanya boleh ada satu Harness aktif per proses.
+package e2e
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/migrations"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/route"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Alamat klien semua request; sama dengan RemoteAddr bawaan httptest
+const clientIP = "192.0.2.1"
+
+// Password semua akun hasil seed
+const SeedPassword = "Rahasia123"
+
+// Account user hasil seed beserta access token-nya
+type Account struct {
+	User  model.User
+	Token string
+}
+
+type Harness struct {
+	Router     *gin.Engine
+	Centrifugo *StubCentrifugo
+
+	// Satu akun per role: admin, dispatcher, finance, kurir, customer,
+	// ditambah "outsider" (customer yang tidak terkait pesanan mana pun)
+	Accounts map[string]*Account
+
+	admin  *gorm.DB
+	dbName string
+}
+
+// New membuat database sementara di server admin (admin.Name biasanya
+// "postgres"), menjalankan migrasi, lalu merakit router dan seed user.
+func New(admin config.DBConfig) (*Harness, error) {
+	adminDB, err := gorm.Open(postgres.Open(admin.DSN()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, fmt.Errorf("koneksi admin database gagal: %w", err)
+	}
+
+	h := &Harness{
+		admin:      adminDB,
+		dbName:     fmt.Sprintf("misi_paket_e2e_%d", time.Now().UnixNano()),
+		Centrifugo: NewStubCentrifugo(randomHex(16)),
+		Accounts:   map[string]*Account{},
+	}
+	if err := adminDB.Exec("CREATE DATABASE " + h.dbName).Error; err != nil {
+		h.Centrifugo.Close()
+		return nil, fmt.Errorf("gagal membuat database %s: %w", h.dbName, err)
+	}
+
+	if err := h.setup(admin); err != nil {
+		h.Close()
+		return nil, err
+	}
+	return h, nil
+}
+
+// setup memuat konfigurasi lewat config.Load supaya jalurnya sama dengan
+// server. Environment diisi lebih dulu sehingga .env tidak ikut terpakai.
+func (h *Harness) setup(admin config.DBConfig) error {
+	env := map[string]string{
+		"APP_ENV":                 "test",
+		"DB_HOST":                 admin.Host,
+		"DB_PORT":                 admin.Port,
+		"DB_USER":                 admin.User,
+		"DB_PASSWORD":             admin.Password,
+		"DB_NAME":                 h.dbName,
+		"DB_SSLMODE":              admin.SSLMode,
+		"DB_CONNECT_ATTEMPTS":     "1",
+		"JWT_ALG":                 "HS256",
+		"JWT_KID":                 "e2e",
+		"JWT_SECRET":              randomHex(32),
+		"CENTRIFUGO_API_URL":      h.Centrifugo.APIURL(),
+		"CENTRIFUGO_API_KEY":      h.Centrifugo.APIKey,
+		"CENTRIFUGO_SECRET":       randomHex(32),
+		"CENTRIFUGO_PROXY_SECRET": randomHex(16),
+		"RATE_LIMIT_STORE":        "memory",
+		"UPLOAD_DIR":              os.TempDir(),
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := utils.LoadKeys(cfg.JWT, cfg.Env); err != nil {
+		return err
+	}
+	utils.SetPasswordPolicy(cfg.Password.MinLength, cfg.Password.BcryptCost)
+	centrifugo.Configure(cfg.Centrifugo)
+
+	if err := config.ConnectDB(cfg.DB); err != nil {
+		return err
+	}
+	if err := migrations.Up(config.DB); err != nil {
+		return err
+	}
+	permission.Load()
+	ratelimit.SetStore(ratelimit.NewMemoryStore())
+
+	gin.SetMode(gin.TestMode)
+	h.Router = gin.New()
+	if err := h.Router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+	route.SetupRoutes(h.Router, service.New(
+		repository.NewPostgres(config.DB),
+		service.PublisherFunc(centrifugo.Publish),
+		auth.RevokeUserSessions,
+	))
+
+	return h.seed()
+}
+
+// seed membuat satu user per role dan langsung membuka sesi untuknya,
+// tanpa lewat /login supaya tidak memakan kuota rate limit auth
+func (h *Harness) seed() error {
+	hash, err := utils.HashPassword(SeedPassword)
+	if err != nil {
+		return err
+	}
+
+	kendaraan, plat := "Motor", "B 1234 E2E"
+	users := []model.User{
+		{Name: "Admin E2E", Role: permission.AdminRole},
+		{Name: "Dispatcher E2E", Role: "dispatcher"},
+		{Name: "Finance E2E", Role: "finance"},
+		{Name: "Kurir E2E", Role: "kurir", Kendaraan: &kendaraan, PlatNomor: &plat, Status: "online"},
+		{Name: "Customer E2E", Role: "customer"},
+		{Name: "Outsider E2E", Role: "customer"},
+	}
+	keys := []string{"admin", "dispatcher", "finance", "kurir", "customer", "outsider"}
+
+	for i, user := range users {
+		user.Email = keys[i] + "@e2e.test"
+		user.Phone = "0812000000" + strconv.Itoa(10+i)
+		user.Password = hash
+		user.StatusKerja = "aktif"
+		if user.Status == "" {
+			user.Status = "offline"
+		}
+		if err := config.DB.Create(&user).Error; err != nil {
+			return fmt.Errorf("seed %s: %w", keys[i], err)
+		}
+
+		tokens, err := auth.StartSession(user, auth.Device{Name: "e2e", Platform: "test", IP: clientIP})
+		if err != nil {
+			return fmt.Errorf("sesi %s: %w", keys[i], err)
+		}
+		h.Accounts[keys[i]] = &Account{User: user, Token: tokens.AccessToken}
+	}
+	return nil
+}
+
+// Close menutup koneksi dan menghapus database sementara
+func (h *Harness) Close() error {
+	config.CloseDB()
+	config.DB = nil
+	h.Centrifugo.Close()
+
+	err := h.admin.Exec("DROP DATABASE IF EXISTS " + h.dbName + " WITH (FORCE)").Error
+	if sqlDB, dbErr := h.admin.DB(); dbErr == nil {
+		sqlDB.Close()
+	}
+	return err
+}
+
+// Response hasil satu request ke router
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Decode membaca body JSON ke v
+func (r Response) Decode(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("body bukan JSON yang diharapkan (%v): %s", err, r.Body)
+	}
+	return nil
+}
+
+// Expect memastikan status sesuai; body ikut di pesan error supaya mudah dilacak
+func (r Response) Expect(status int) error {
+	if r.Status != status {
+		return fmt.Errorf("status %d, diharapkan %d: %s", r.Status, status, r.Body)
+	}
+	return nil
+}
+
+// Do mengirim request ke router. as nil berarti tanpa token; body nil
+// berarti tanpa body, selain itu di-encode sebagai JSON.
+func (h *Harness) Do(method, path string, as *Account, body interface{}) Response {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			panic(err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if as != nil {
+		req.Header.Set("Authorization", "Bearer "+as.Token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.Router.ServeHTTP(rec, req)
+	return Response{Status: rec.Code, Header: rec.Header(), Body: rec.Body.Bytes()}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
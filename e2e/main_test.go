@@ -0,0 +1,65 @@
+// Test end-to-end berjalan terhadap server Postgres sementara, mis.
+//
+//	docker run --rm -d -p 55432:5432 -e POSTGRES_PASSWORD=postgres postgres:16
+//	E2E_DB_HOST=localhost E2E_DB_PORT=55432 go test ./e2e
+//
+// Tanpa E2E_DB_HOST semua test di-skip. Database uji dibuat dengan nama unik
+// lalu di-drop setelah selesai, jadi jangan arahkan ke server production.
+package e2e
+
+import (
+	"log"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+)
+
+// Satu Harness dipakai bersama semua test karena memakai config global
+var (
+	shared     *Harness
+	sharedErr  error
+	sharedOnce sync.Once
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if shared != nil {
+		if err := shared.Close(); err != nil {
+			log.Println("⚠️ Database uji gagal dihapus:", err)
+		}
+	}
+	os.Exit(code)
+}
+
+// harness menyiapkan Harness pada pemakaian pertama, atau men-skip test
+// kalau server Postgres uji tidak dikonfigurasi
+func harness(t *testing.T) *Harness {
+	t.Helper()
+	if os.Getenv("E2E_DB_HOST") == "" {
+		t.Skip("E2E_DB_HOST tidak diisi, test end-to-end di-skip")
+	}
+
+	sharedOnce.Do(func() {
+		shared, sharedErr = New(config.DBConfig{
+			Host:     os.Getenv("E2E_DB_HOST"),
+			Port:     env("E2E_DB_PORT", "5432"),
+			User:     env("E2E_DB_USER", "postgres"),
+			Password: env("E2E_DB_PASSWORD", "postgres"),
+			Name:     env("E2E_DB_NAME", "postgres"),
+			SSLMode:  env("E2E_DB_SSLMODE", "disable"),
+		})
+	})
+	if sharedErr != nil {
+		t.Fatalf("harness gagal disiapkan: %v", sharedErr)
+	}
+	return shared
+}
+
+func env(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+)
+
+// CentrifugoHandler endpoint yang dipanggil server Centrifugo
+type CentrifugoHandler struct {
+	users *service.UserService
+}
+
+func NewCentrifugoHandler(users *service.UserService) *CentrifugoHandler {
+	return &CentrifugoHandler{users: users}
+}
+
+// GET /centrifugo/subscription-token?channel=chat:12
+// Token untuk subscribe satu channel, hanya diberikan ke peserta pesanan
+func GenerateSubscriptionToken(c *gin.Context) {
+	channel := c.Query("channel")
+	if channel == "" {
+		c.Error(apperror.MissingParameter.With("param", "channel"))
+		return
+	}
+
+	actor := policy.ActorFrom(c)
+	if err := centrifugo.Authorize(actor, channel); err != nil {
+		c.Error(channelError(err))
+		return
+	}
+
+	token, err := centrifugo.SubscriptionToken(actor.ID, channel)
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":   token,
+		"channel": channel,
+	})
+}
+
+// Kode error subscribe proxy Centrifugo; 103 = permission denied
+const centrifugoPermissionDenied = 103
+
+// POST /centrifugo/subscribe — subscribe proxy dari server Centrifugo.
+// Centrifugo mengirim {user, channel}; user berasal dari connection token
+// yang kita tandatangani, role-nya diambil ulang dari database.
+func (h *CentrifugoHandler) SubscribeProxy(c *gin.Context) {
+	// Header rahasia diatur lewat proxy_static_http_headers di config Centrifugo.
+	// Secret kosong (belum dikonfigurasi) juga ditolak.
+	secret := centrifugo.ProxySecret()
+	header := c.GetHeader("X-Centrifugo-Proxy-Secret")
+	if secret == "" || header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(secret)) != 1 {
+		c.Error(apperror.ProxyUnauthorized)
+		return
+	}
+
+	var req struct {
+		User    string `json:"user"`
+		Channel string `json:"channel"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest)
+		return
+	}
+
+	deny := gin.H{"error": gin.H{"code": centrifugoPermissionDenied, "message": "permission denied"}}
+
+	userID, err := strconv.ParseUint(req.User, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusOK, deny)
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), uint(userID))
+	if errors.Is(err, service.ErrUserNotFound) {
+		c.JSON(http.StatusOK, deny)
+		return
+	}
+	if err != nil {
+		c.Error(apperror.Internal.Wrap(err))
+		return
+	}
+
+	actor := policy.Actor{ID: user.ID, Role: user.Role}
+	if err := centrifugo.Authorize(actor, req.Channel); err != nil {
+		middleware.Logf(c, "⛔ Subscribe ditolak: user=%s channel=%s (%v)", req.User, req.Channel, err)
+		c.JSON(http.StatusOK, deny)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"result": gin.H{}})
+}
+
+func channelError(err error) *apperror.Error {
+	switch {
+	case errors.Is(err, centrifugo.ErrUnknownChannel):
+		return apperror.UnknownChannel
+	case errors.Is(err, centrifugo.ErrChannelNotFound):
+		return apperror.ChannelNotFound
+	case errors.Is(err, centrifugo.ErrForbidden):
+		return apperror.ChannelForbidden
+	default:
+		return apperror.Internal.Wrap(err)
+	}
+}
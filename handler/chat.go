@@ -1,166 +1,89 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
 	"github.com/mubarok-ridho/misi-paket.backend/config"
-	"github.com/mubarok-ridho/misi-paket.backend/model"
-	// "github.com/mubarok-ridho/misi-paket.backend/config"
-	// "github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
 )
 
-// Struct untuk payload publish ke Centrifugo
-type CentrifugoPublishPayload struct {
-	Method string                  `json:"method"`
-	Params CentrifugoPublishParams `json:"params"`
+// ChatHandler endpoint chat per pesanan; aturan bisnisnya ada di service.ChatService
+type ChatHandler struct {
+	chat *service.ChatService
 }
 
+func NewChatHandler(chat *service.ChatService) *ChatHandler {
+	return &ChatHandler{chat: chat}
+}
+
+// sender_id, receiver_id dan sender masih diterima dari aplikasi lama,
+// tapi nilainya ditentukan dari token dan data pesanan
 type SendChatInput struct {
 	OrderIDStr string `json:"order_id" binding:"required"`
-	SenderID   uint   `json:"sender_id" binding:"required"`
-	ReceiverID uint   `json:"receiver_id" binding:"required"`
-	Sender     string `json:"sender" binding:"required"`
+	SenderID   uint   `json:"sender_id"`
+	ReceiverID uint   `json:"receiver_id"`
+	Sender     string `json:"sender"`
 	Content    string `json:"message" binding:"required"`
 }
-type CentrifugoPublishParams struct {
-	Channel string        `json:"channel"`
-	Data    model.Message `json:"data"`
-}
-
-// Struct untuk menerima body request Flutter
-type ChatMessage struct {
-	OrderID string `json:"order_id"`
-	Sender  string `json:"sender"` // "kurir" atau "customer"
-	Message string `json:"message"`
-}
 
 // Handler kirim chat ke Centrifugo (POST /chat/send)
-func SendChatMessage(c *gin.Context) {
+func (h *ChatHandler) SendChatMessage(c *gin.Context) {
 	var input SendChatInput
 
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		lang := i18n.FromContext(c)
+		c.Error(apperror.ValidationFailed.
+			WithMessage(utils.ValidationMessage(lang, err)).
+			WithFields(utils.ValidationErrors(lang, err)))
 		return
 	}
 	orderID, err := strconv.ParseUint(input.OrderIDStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id harus berupa angka"})
-		return
-	}
-	// 1. Simpan ke databaseh
-	newMessage := model.Message{
-		OrderID:    uint(orderID),
-		SenderID:   input.SenderID,
-		ReceiverID: input.ReceiverID,
-		Content:    input.Content,
-		SentAt:     time.Now(),
-		IsRead:     false,
-	}
-
-	if err := config.DB.Create(&newMessage).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan pesan"})
-		return
-	}
-
-	// Send ke Centrifugow
-	msg := ChatMessage{
-		OrderID: fmt.Sprint(orderID),
-		Sender:  input.Sender, // ✅ penting!
-		Message: input.Content,
-	}
-
-	channel := fmt.Sprintf("chat:%s", msg.OrderID)
-
-	payload := CentrifugoPublishParams{
-		Channel: channel,
-		Data:    newMessage,
-	}
-	reqbody := CentrifugoPublishPayload{
-		Method: "publish",
-		Params: payload,
-	}
-	jsonPayload, err := json.Marshal(reqbody)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encode payload"})
+		c.Error(apperror.InvalidParameter.With("param", "order_id"))
 		return
 	}
-	fmt.Println(string(jsonPayload))
-	centrifugoURL := os.Getenv("CENTRIFUGO_API_URL")    // contoh: http://localhost:8000/api
-	centrifugoAPIKey := os.Getenv("CENTRIFUGO_API_KEY") // contoh: secret_api_key
 
-	req, err := http.NewRequest("POST", centrifugoURL, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create request"})
+	// Pesan disimpan dulu, baru dikirim ke Centrifugo. Detail jawaban
+	// Centrifugo hanya dicatat di log, tidak dikirim ke client.
+	if _, err := h.chat.Send(c.Request.Context(), policy.ActorFrom(c), uint(orderID), input.Content); err != nil {
+		respondChatError(c, err)
 		return
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", centrifugoAPIKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send to Centrifugo"})
-		return
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		c.JSON(http.StatusBadGateway, gin.H{
-			"error":   "centrifugo publish failed",
-			"details": string(bodyBytes),
-		})
-		return
-	}
-	fmt.Println(string(bodyBytes))
 	c.JSON(http.StatusOK, gin.H{"status": "message sent"})
 }
 
-// Handler untuk generate token JWT Centrifugo (GET /centrifugo/token?user_id=xxx)
+// Handler untuk generate token JWT Centrifugo (GET /centrifugo/token)
+// Token selalu untuk user yang login; ?user_id= hanya dicek kecocokannya.
 func GenerateCentrifugoToken(c *gin.Context) {
-	userID := c.Query("user_id")
-	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id required"})
-		return
-	}
-
-	secret := os.Getenv("CENTRIFUGO_SECRET")
-	if secret == "" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "missing CENTRIFUGO_SECRET env"})
+	userID := fmt.Sprint(c.GetUint("userID"))
+	if q := c.Query("user_id"); q != "" && q != userID {
+		c.Error(apperror.TokenNotOwn)
 		return
 	}
 
-	// Membuat token JWT
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": userID,
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
-	})
-
-	tokenString, err := token.SignedString([]byte(secret))
+	// Token koneksi hanya berisi identitas; akses ke channel chat/tracking
+	// lewat subscription token atau subscribe proxy
+	tokenString, err := centrifugo.ConnectionToken(c.GetUint("userID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "token signing failed"})
+		c.Error(apperror.Internal.Wrap(err))
 		return
 	}
 
-	fmt.Println("DEBUG Backend Generated JWT:", tokenString) // Tambahkan ini
-
-	// ✅ Debug log untuk development
-	if os.Getenv("APP_ENV") == "development" {
-		log.Println("✅ JWT token generated with:")
-		log.Println("  - userID:", userID)
-		log.Println("  - secret : [", secret, "]") // HATI-HATI! hanya untuk dev!
-		log.Println("  - token  :", tokenString)
+	// ✅ Debug log untuk development (token & secret tidak ikut dicetak)
+	if config.Get().Env == "development" {
+		middleware.Logf(c, "✅ Centrifugo token generated for userID: %s", userID)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -169,26 +92,51 @@ func GenerateCentrifugoToken(c *gin.Context) {
 	})
 }
 
-func GetMessagesByOrderID(c *gin.Context) {
+func (h *ChatHandler) GetMessagesByOrderID(c *gin.Context) {
 	orderIDParam := c.Param("order_id")
 	orderID, err := strconv.ParseUint(orderIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id tidak valid"})
+		c.Error(apperror.InvalidParameter.With("param", "order_id"))
 		return
 	}
 
-	var messages []model.Message
-	if err := config.DB.
-		Preload("Sender").
-		Where("order_id = ?", orderID).
-		Order("sent_at ASC").
-		Find(&messages).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil pesan dari database"})
+	messages, err := h.chat.History(c.Request.Context(), policy.ActorFrom(c), uint(orderID))
+	if err != nil {
+		respondChatError(c, err)
 		return
 	}
 
-	// Boleh langsung kirim semua data lengkap
 	c.JSON(http.StatusOK, gin.H{
-		"messages": messages,
+		"messages": dto.NewMessageViews(messages),
 	})
 }
+
+// DELETE /messages/order/:id
+func (h *ChatHandler) DeleteMessagesByOrderID(c *gin.Context) {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(apperror.InvalidParameter.With("param", "order_id"))
+		return
+	}
+
+	if err := h.chat.Clear(c.Request.Context(), policy.ActorFrom(c), uint(orderID)); err != nil {
+		respondChatError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.Msg(c, "msg.chat_cleared")})
+}
+
+// respondChatError menerjemahkan error ChatService
+func respondChatError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound):
+		c.Error(apperror.OrderNotFound)
+	case errors.Is(err, service.ErrForbidden):
+		c.Error(apperror.ChatForbidden)
+	case errors.Is(err, service.ErrPublish):
+		c.Error(apperror.RealtimeFailed.Wrap(err))
+	default:
+		c.Error(apperror.Internal.Wrap(err))
+	}
+}
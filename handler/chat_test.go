@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+)
+
+// asActor pengganti JWTAuthMiddleware: identitas diambil dari header test
+func asActor(c *gin.Context) {
+	var id uint
+	fmt.Sscan(c.GetHeader("X-Test-User"), &id)
+	c.Set("userID", id)
+	c.Set("role", c.GetHeader("X-Test-Role"))
+	c.Next()
+}
+
+func TestChatOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	repos := repository.NewMemory()
+
+	var customerA, customerB, kurirA, kurirB policy.Actor
+	for _, u := range []struct {
+		name, role string
+		actor      *policy.Actor
+	}{
+		{"customer-a", "customer", &customerA},
+		{"customer-b", "customer", &customerB},
+		{"kurir-a", "kurir", &kurirA},
+		{"kurir-b", "kurir", &kurirB},
+	} {
+		user := model.User{Name: u.name, Email: u.name + "@example.com", Role: u.role, StatusKerja: "aktif"}
+		if err := repos.Users.Save(ctx, &user); err != nil {
+			t.Fatal(err)
+		}
+		*u.actor = policy.Actor{ID: user.ID, Role: u.role}
+	}
+
+	svc := service.New(repos, service.PublisherFunc(func(context.Context, string, interface{}) error { return nil }),
+		func(uint, uint) error { return nil })
+	orderA := model.Order{CustomerID: customerA.ID, KurirID: kurirA.ID, Layanan: "antar barang"}
+	orderB := model.Order{CustomerID: customerB.ID, KurirID: kurirB.ID, Layanan: "antar barang"}
+	for _, o := range []*model.Order{&orderA, &orderB} {
+		if err := svc.Orders.Create(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chat := NewChatHandler(svc.Chat)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler(), asActor)
+	r.POST("/chat/send", chat.SendChatMessage)
+	r.GET("/chat/load/:order_id", chat.GetMessagesByOrderID)
+	r.DELETE("/messages/order/:id", chat.DeleteMessagesByOrderID)
+
+	send := func(o model.Order) map[string]string {
+		return map[string]string{"order_id": fmt.Sprint(o.ID), "message": "halo"}
+	}
+	cases := []struct {
+		name   string
+		actor  policy.Actor
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"customer chats on own order", customerA, http.MethodPost, "/chat/send", send(orderA), http.StatusOK},
+		{"customer cannot chat on other's order", customerA, http.MethodPost, "/chat/send", send(orderB), http.StatusForbidden},
+		{"customer loads own chat", customerA, http.MethodGet, fmt.Sprintf("/chat/load/%d", orderA.ID), nil, http.StatusOK},
+		{"customer cannot load other's chat", customerA, http.MethodGet, fmt.Sprintf("/chat/load/%d", orderB.ID), nil, http.StatusForbidden},
+		{"customer cannot clear other's chat", customerA, http.MethodDelete, fmt.Sprintf("/messages/order/%d", orderB.ID), nil, http.StatusForbidden},
+		{"kurir chats on assigned order", kurirA, http.MethodPost, "/chat/send", send(orderA), http.StatusOK},
+		{"kurir cannot chat on unassigned order", kurirA, http.MethodPost, "/chat/send", send(orderB), http.StatusForbidden},
+		{"kurir cannot load unassigned chat", kurirA, http.MethodGet, fmt.Sprintf("/chat/load/%d", orderB.ID), nil, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if tc.body != nil {
+				json.NewEncoder(&buf).Encode(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, tc.path, &buf)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Test-User", fmt.Sprint(tc.actor.ID))
+			req.Header.Set("X-Test-Role", tc.actor.Role)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+
+	// Pesan yang ditolak tidak boleh tersimpan di pesanan orang lain
+	messages, err := repos.Messages.ListByOrder(ctx, orderB.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 0 {
+		t.Fatalf("order B has %d messages, want 0", len(messages))
+	}
+}
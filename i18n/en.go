@@ -0,0 +1,133 @@
+package i18n
+
+// Katalog Bahasa Inggris
+var en = map[string]string{
+	// Error, key = kode apperror
+	"invalid_request":           "Invalid request format",
+	"validation_failed":         "Invalid data",
+	"invalid_parameter":         "{param} is invalid",
+	"missing_parameter":         "{param} is required",
+	"invalid_date":              "Date {param} must be in YYYY-MM-DD format",
+	"unsupported_format":        "Format must be csv or xlsx",
+	"invalid_sort":              "Unknown sort column",
+	"invalid_sort_order":        "order must be asc or desc",
+	"weak_password":             "Password does not meet the policy",
+	"password_reused":           "New password must differ from your last 5 passwords",
+	"reset_channel_invalid":     "Channel must be email or otp",
+	"reset_credentials_missing": "Provide a reset token, or a phone number and OTP code",
+	"reset_token_invalid":       "Reset token is invalid or has expired",
+	"user_no_email":             "User has no email to receive a reset link",
+	"phone_missing":             "Phone number is not set",
+	"kurir_required":            "Courier is required",
+	"unknown_role":              "Unknown role",
+	"unknown_permission":        "Unknown permission: {permission}",
+	"own_role_change":           "You cannot change your own role",
+	"unknown_channel":           "Unknown channel",
+	"document_missing":          "File {document} is required",
+	"document_invalid":          "File {document} must be a JPG, PNG or PDF of at most 5 MB",
+	"document_expired":          "{document} has expired",
+	"token_missing":             "Token not found",
+	"token_malformed":           "Malformed token",
+	"token_invalid":             "Invalid token",
+	"session_invalid":           "Session is no longer valid, please log in again",
+	"refresh_token_reused":      "Refresh token was already used, session terminated",
+	"invalid_credentials":       "Incorrect email/phone number or password",
+	"account_inactive":          "Your account is inactive",
+	"account_pending":           "Your courier registration is still under review",
+	"wrong_password":            "Current password is incorrect",
+	"otp_invalid":               "OTP code is incorrect or has expired",
+	"otp_exhausted":             "Too many wrong OTP attempts, request a new code",
+	"proxy_unauthorized":        "Unauthorized",
+	"forbidden":                 "Access denied",
+	"permission_denied":         "Access denied, missing permission {permission}",
+	"order_forbidden":           "Access denied for this order",
+	"chat_forbidden":            "Access denied for this order's chat",
+	"kurir_forbidden":           "Access denied for this courier's data",
+	"location_forbidden":        "Access denied for this courier's location",
+	"channel_forbidden":         "No access to this channel",
+	"token_not_own":             "Tokens can only be issued for your own account",
+	"role_escalation":           "You cannot manage a role with more access than your own",
+	"kurir_inactive":            "Courier is not approved or inactive",
+	"route_not_found":           "Endpoint not found",
+	"user_not_found":            "User not found",
+	"order_not_found":           "Order not found",
+	"kurir_not_found":           "Courier not found",
+	"location_not_found":        "Location not available yet",
+	"session_not_found":         "Session not found",
+	"login_lock_not_found":      "Login lock not found",
+	"application_not_found":     "Registration not found",
+	"document_not_found":        "Document not found",
+	"role_not_found":            "Role not found",
+	"channel_not_found":         "Order for this channel not found",
+	"email_taken":               "Email is already registered",
+	"phone_taken":               "Phone number is already registered",
+	"role_exists":               "Role already exists",
+	"role_built_in":             "Built-in roles cannot be changed this way",
+	"role_in_use":               "Role is still assigned to users",
+	"application_reviewed":      "Registration has already been reviewed",
+	"too_many_requests":         "Too many requests, try again shortly",
+	"login_locked":              "Too many login attempts, try again in {seconds} seconds",
+	"reset_recently_sent":       "A reset link was just sent, try again shortly",
+	"otp_too_soon":              "Please wait before requesting a new code",
+	"otp_too_many":              "Too many code requests, try again later",
+	"internal_error":            "Something went wrong on the server",
+	"realtime_failed":           "Failed to deliver to the realtime server",
+	"service_unavailable":       "The service is temporarily unavailable, please try again",
+
+	// Validasi input
+	"validation.malformed": "Invalid data format",
+	"validation.required":  "{field} is required",
+	"validation.email":     "{field} must be a valid email address",
+	"validation.phone_id":  "{field} must be a valid Indonesian phone number (e.g. 081234567890)",
+	"validation.min_len":   "{field} must be at least {param} characters",
+	"validation.min":       "{field} must be at least {param}",
+	"validation.max_len":   "{field} must be at most {param} characters",
+	"validation.max":       "{field} must be at most {param}",
+	"validation.oneof":     "{field} must be one of: {param}",
+	"validation.invalid":   "{field} is invalid",
+
+	// Kebijakan password
+	"password.too_short": "password must be at least {min} characters",
+	"password.too_long":  "password must be at most {max} characters",
+	"password.weak":      "password must contain letters and digits",
+	"password.common":    "password is too common, choose another one",
+	"password.personal":  "password must not contain your name or email",
+
+	// Response sukses
+	"msg.api_running":            "FaiExpress API is running!",
+	"msg.register_success":       "Registration successful",
+	"msg.kurir_created":          "Courier registered successfully",
+	"msg.logout_success":         "Logged out successfully",
+	"msg.logout_all_success":     "Logged out from all devices",
+	"msg.password_changed":       "Password changed, other devices have been logged out",
+	"msg.password_reset_done":    "Password reset, please log in again",
+	"msg.reset_instructions":     "If the account exists, password reset instructions have been sent",
+	"msg.reset_link_sent":        "A password reset link has been sent to the user's email",
+	"msg.otp_sent_if_registered": "If the number is registered, an OTP code has been sent",
+	"msg.otp_sent":               "OTP code sent",
+	"msg.phone_already_verified": "Phone number is already verified",
+	"msg.phone_verified":         "Phone number verified",
+	"msg.session_revoked":        "Device logged out",
+	"msg.user_sessions_revoked":  "All of the user's devices have been logged out",
+	"msg.login_unlocked":         "Login lock removed",
+	"msg.user_login_unlocked":    "User login lock removed",
+	"msg.role_created":           "Role created",
+	"msg.role_deleted":           "Role deleted",
+	"msg.role_permissions_saved": "Role permissions updated",
+	"msg.user_role_changed":      "User role changed",
+	"msg.profile_updated":        "Profile updated",
+	"msg.kurir_profile_updated":  "Courier profile updated",
+	"msg.status_updated":         "Status updated",
+	"msg.user_deactivated":       "Courier deactivated",
+	"msg.order_created":          "Order created",
+	"msg.order_deleted":          "Order deleted",
+	"msg.billing_updated":        "Billing updated (breakdown not stored)",
+	"msg.payment_validated":      "Payment validated",
+	"msg.payment_method_updated": "Payment method updated",
+	"msg.location_updated":       "Location updated",
+	"msg.kurir_location_updated": "Courier location updated",
+	"msg.chat_sent":              "Message sent",
+	"msg.chat_cleared":           "All messages deleted",
+	"msg.kurir_application_sent": "Courier registration submitted, awaiting admin verification",
+	"msg.application_reviewed":   "Registration reviewed",
+}
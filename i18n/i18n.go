@@ -0,0 +1,85 @@
+// Package i18n menyimpan katalog pesan API per bahasa. Key pesan error sama
+// dengan kode apperror; pesan lain memakai prefix (msg., validation.,
+// password.). Bahasa dipilih dari header Accept-Language, default Indonesia.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const Default = "id"
+
+var catalogs = map[string]map[string]string{
+	"id": id,
+	"en": en,
+}
+
+// Supported bahasa yang punya katalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate memilih bahasa dari header Accept-Language, mis.
+// "en-US,en;q=0.9,id;q=0.8". Bahasa dengan q tertinggi yang didukung
+// menang; kalau tidak ada yang cocok dipakai Default.
+func Negotiate(header string) string {
+	type option struct {
+		lang string
+		q    float64
+	}
+	var options []option
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > 0 && Supported(base) {
+			options = append(options, option{base, q})
+		}
+	}
+	if len(options) == 0 {
+		return Default
+	}
+	sort.SliceStable(options, func(i, j int) bool { return options[i].q > options[j].q })
+	return options[0].lang
+}
+
+// FromContext bahasa request ini; diisi middleware.Language, atau
+// dinegosiasikan langsung kalau middleware tidak terpasang
+func FromContext(c *gin.Context) string {
+	if lang := c.GetString("lang"); lang != "" {
+		return lang
+	}
+	return Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// T pesan untuk key dalam bahasa lang dengan placeholder {nama} diisi dari
+// params. Key yang belum diterjemahkan jatuh ke bahasa Default, lalu ke key-nya.
+func T(lang, key string, params map[string]string) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+	for k, v := range params {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", v)
+	}
+	return msg
+}
+
+// Msg pesan tanpa parameter dalam bahasa request, untuk response sukses
+func Msg(c *gin.Context, key string) string {
+	return T(FromContext(c), key, nil)
+}
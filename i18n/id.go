@@ -0,0 +1,134 @@
+package i18n
+
+// Katalog Bahasa Indonesia (default). Setiap key wajib ada di sini;
+// bahasa lain boleh belum lengkap dan jatuh ke katalog ini.
+var id = map[string]string{
+	// Error, key = kode apperror
+	"invalid_request":           "Format data tidak valid",
+	"validation_failed":         "Data tidak valid",
+	"invalid_parameter":         "{param} tidak valid",
+	"missing_parameter":         "{param} wajib diisi",
+	"invalid_date":              "Format tanggal {param} harus YYYY-MM-DD",
+	"unsupported_format":        "Format harus csv atau xlsx",
+	"invalid_sort":              "Kolom sort tidak dikenali",
+	"invalid_sort_order":        "order harus asc atau desc",
+	"weak_password":             "Password tidak memenuhi kebijakan",
+	"password_reused":           "Password baru tidak boleh sama dengan 5 password terakhir",
+	"reset_channel_invalid":     "Channel harus email atau otp",
+	"reset_credentials_missing": "Sertakan token reset, atau nomor HP dan kode OTP",
+	"reset_token_invalid":       "Token reset tidak valid atau sudah kedaluwarsa",
+	"user_no_email":             "User belum punya email untuk menerima link reset",
+	"phone_missing":             "Nomor HP belum diisi",
+	"kurir_required":            "Kurir tidak boleh kosong",
+	"unknown_role":              "Role tidak dikenal",
+	"unknown_permission":        "Permission tidak dikenal: {permission}",
+	"own_role_change":           "Tidak bisa mengubah role akun sendiri",
+	"unknown_channel":           "Channel tidak dikenal",
+	"document_missing":          "Berkas {document} wajib diunggah",
+	"document_invalid":          "Berkas {document} harus JPG, PNG, atau PDF maksimal 5 MB",
+	"document_expired":          "Masa berlaku {document} sudah habis",
+	"token_missing":             "Token tidak ditemukan",
+	"token_malformed":           "Format token salah",
+	"token_invalid":             "Token tidak valid",
+	"session_invalid":           "Sesi tidak berlaku, silakan login ulang",
+	"refresh_token_reused":      "Refresh token sudah dipakai, sesi dihentikan",
+	"invalid_credentials":       "Email/nomor HP atau password salah",
+	"account_inactive":          "Akun anda tidak aktif",
+	"account_pending":           "Pendaftaran kurir anda masih ditinjau admin",
+	"wrong_password":            "Password lama salah",
+	"otp_invalid":               "Kode OTP salah atau sudah kedaluwarsa",
+	"otp_exhausted":             "Kode OTP sudah terlalu sering salah, minta kode baru",
+	"proxy_unauthorized":        "Unauthorized",
+	"forbidden":                 "Akses ditolak",
+	"permission_denied":         "Akses ditolak, tidak punya izin {permission}",
+	"order_forbidden":           "Akses ditolak untuk pesanan ini",
+	"chat_forbidden":            "Akses ditolak untuk chat pesanan ini",
+	"kurir_forbidden":           "Akses ditolak untuk data kurir ini",
+	"location_forbidden":        "Akses ditolak untuk lokasi kurir ini",
+	"channel_forbidden":         "Tidak punya akses ke channel ini",
+	"token_not_own":             "Token hanya bisa dibuat untuk akun sendiri",
+	"role_escalation":           "Tidak bisa mengatur role yang aksesnya melebihi role Anda",
+	"kurir_inactive":            "Kurir belum disetujui atau tidak aktif",
+	"route_not_found":           "Endpoint tidak ditemukan",
+	"user_not_found":            "User tidak ditemukan",
+	"order_not_found":           "Pesanan tidak ditemukan",
+	"kurir_not_found":           "Kurir tidak ditemukan",
+	"location_not_found":        "Lokasi belum tersedia",
+	"session_not_found":         "Sesi tidak ditemukan",
+	"login_lock_not_found":      "Kunci login tidak ditemukan",
+	"application_not_found":     "Pendaftaran tidak ditemukan",
+	"document_not_found":        "Dokumen tidak ditemukan",
+	"role_not_found":            "Role tidak ditemukan",
+	"channel_not_found":         "Pesanan untuk channel ini tidak ditemukan",
+	"email_taken":               "Email sudah terdaftar",
+	"phone_taken":               "Nomor HP sudah terdaftar",
+	"role_exists":               "Role sudah ada",
+	"role_built_in":             "Role bawaan tidak bisa diubah dengan cara ini",
+	"role_in_use":               "Role masih dipakai user",
+	"application_reviewed":      "Pendaftaran sudah direview",
+	"too_many_requests":         "Terlalu banyak permintaan, coba lagi sebentar",
+	"login_locked":              "Terlalu banyak percobaan login, coba lagi dalam {seconds} detik",
+	"reset_recently_sent":       "Link reset baru saja dikirim, coba lagi sebentar",
+	"otp_too_soon":              "Tunggu sebentar sebelum meminta kode baru",
+	"otp_too_many":              "Terlalu banyak permintaan kode, coba lagi nanti",
+	"internal_error":            "Terjadi kesalahan pada server",
+	"realtime_failed":           "Gagal mengirim ke server realtime",
+	"service_unavailable":       "Layanan sedang tidak tersedia, coba lagi sebentar",
+
+	// Validasi input (utils.ValidationErrors)
+	"validation.malformed": "Format data tidak valid",
+	"validation.required":  "{field} wajib diisi",
+	"validation.email":     "{field} harus berupa alamat email yang valid",
+	"validation.phone_id":  "{field} harus nomor HP Indonesia yang valid (contoh: 081234567890)",
+	"validation.min_len":   "{field} minimal {param} karakter",
+	"validation.min":       "{field} minimal {param}",
+	"validation.max_len":   "{field} maksimal {param} karakter",
+	"validation.max":       "{field} maksimal {param}",
+	"validation.oneof":     "{field} harus salah satu dari: {param}",
+	"validation.invalid":   "{field} tidak valid",
+
+	// Kebijakan password (utils.ValidatePassword)
+	"password.too_short": "password minimal {min} karakter",
+	"password.too_long":  "password maksimal {max} karakter",
+	"password.weak":      "password harus mengandung huruf dan angka",
+	"password.common":    "password terlalu umum, pilih yang lain",
+	"password.personal":  "password tidak boleh memuat nama atau email",
+
+	// Response sukses
+	"msg.api_running":            "FaiExpress API is running!",
+	"msg.register_success":       "Registrasi berhasil",
+	"msg.kurir_created":          "Kurir berhasil didaftarkan",
+	"msg.logout_success":         "Logout berhasil",
+	"msg.logout_all_success":     "Logout dari semua perangkat berhasil",
+	"msg.password_changed":       "Password berhasil diubah, perangkat lain sudah dikeluarkan",
+	"msg.password_reset_done":    "Password berhasil direset, silakan login ulang",
+	"msg.reset_instructions":     "Jika akun terdaftar, instruksi reset password sudah dikirim",
+	"msg.reset_link_sent":        "Link reset password sudah dikirim ke email user",
+	"msg.otp_sent_if_registered": "Jika nomor terdaftar, kode OTP sudah dikirim",
+	"msg.otp_sent":               "Kode OTP sudah dikirim",
+	"msg.phone_already_verified": "Nomor HP sudah terverifikasi",
+	"msg.phone_verified":         "Nomor HP berhasil diverifikasi",
+	"msg.session_revoked":        "Perangkat berhasil dikeluarkan",
+	"msg.user_sessions_revoked":  "Semua perangkat user berhasil dikeluarkan",
+	"msg.login_unlocked":         "Kunci login dibuka",
+	"msg.user_login_unlocked":    "Kunci login user dibuka",
+	"msg.role_created":           "Role berhasil dibuat",
+	"msg.role_deleted":           "Role berhasil dihapus",
+	"msg.role_permissions_saved": "Permission role berhasil diperbarui",
+	"msg.user_role_changed":      "Role user berhasil diubah",
+	"msg.profile_updated":        "Profil berhasil diperbarui",
+	"msg.kurir_profile_updated":  "Profil kurir berhasil diperbarui",
+	"msg.status_updated":         "Status berhasil diperbarui",
+	"msg.user_deactivated":       "Kurir berhasil dinonaktifkan",
+	"msg.order_created":          "Pesanan berhasil dibuat",
+	"msg.order_deleted":          "Order berhasil dihapus",
+	"msg.billing_updated":        "Tagihan berhasil diperbarui (rincian tidak disimpan)",
+	"msg.payment_validated":      "Pembayaran divalidasi",
+	"msg.payment_method_updated": "Metode bayar diperbarui",
+	"msg.location_updated":       "Lokasi diperbarui",
+	"msg.kurir_location_updated": "Lokasi kurir diperbarui",
+	"msg.chat_sent":              "Pesan terkirim",
+	"msg.chat_cleared":           "Semua pesan berhasil dihapus",
+	"msg.kurir_application_sent": "Pendaftaran kurir terkirim, tunggu verifikasi admin",
+	"msg.application_reviewed":   "Pendaftaran berhasil direview",
+}
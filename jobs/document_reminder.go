@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/notifier"
+)
+
+const (
+	reminderInterval = 12 * time.Hour
+	reminderWindow   = 30 * 24 * time.Hour // ingatkan 30 hari sebelum habis
+	reminderRepeat   = 7 * 24 * time.Hour  // ulangi tiap minggu sampai diperbarui
+)
+
+// StartDocumentReminder berjalan di background sampai ctx selesai,
+// mengingatkan kurir yang SIM/STNK-nya hampir atau sudah habis masa berlaku
+func StartDocumentReminder(ctx context.Context) {
+	running.Add(1)
+	go func() {
+		defer running.Done()
+
+		ticker := time.NewTicker(reminderInterval)
+		defer ticker.Stop()
+
+		for {
+			sendDocumentReminders(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func sendDocumentReminders(ctx context.Context) {
+	if config.DB == nil {
+		return
+	}
+
+	now := time.Now()
+	var docs []model.KurirDocument
+	if err := config.DB.
+		Where("expires_at IS NOT NULL AND expires_at < ?", now.Add(reminderWindow)).
+		Where("reminder_sent_at IS NULL OR reminder_sent_at < ?", now.Add(-reminderRepeat)).
+		Find(&docs).Error; err != nil {
+		log.Println("⚠️ Gagal mengambil dokumen kurir:", err)
+		return
+	}
+
+	for _, doc := range docs {
+		var kurir model.User
+		if err := config.DB.First(&kurir, doc.UserID).Error; err != nil || kurir.Phone == "" {
+			continue
+		}
+
+		msg := fmt.Sprintf("Halo %s, masa berlaku %s kamu berakhir %s. Segera perbarui dan kirim ke admin FaiExpress.",
+			kurir.Name, strings.ToUpper(doc.Type), doc.ExpiresAt.Format("02-01-2006"))
+		if doc.ExpiresAt.Before(now) {
+			msg = fmt.Sprintf("Halo %s, %s kamu sudah habis masa berlakunya sejak %s. Segera perbarui dan kirim ke admin FaiExpress.",
+				kurir.Name, strings.ToUpper(doc.Type), doc.ExpiresAt.Format("02-01-2006"))
+		}
+
+		if err := notifier.Send(ctx, kurir.Phone, msg); err != nil {
+			log.Println("⚠️ Gagal mengirim pengingat dokumen:", err)
+			continue
+		}
+		config.DB.Model(&doc).Update("reminder_sent_at", now)
+	}
+}
@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// running menghitung worker background yang masih jalan
+var running sync.WaitGroup
+
+// Wait menunggu semua worker berhenti (setelah ctx Start* dibatalkan),
+// atau sampai ctx di sini habis
+func Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,26 +1,138 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
 	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/jobs"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/migrations"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
 	"github.com/mubarok-ridho/misi-paket.backend/route"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
 )
 
 func main() {
-	config.ConnectDB()
+	// Semua konfigurasi divalidasi sebelum server menerima request
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	if err := utils.LoadKeys(cfg.JWT, cfg.Env); err != nil {
+		log.Fatal("❌ Konfigurasi JWT tidak valid: ", err)
+	}
+	utils.SetPasswordPolicy(cfg.Password.MinLength, cfg.Password.BcryptCost)
+	centrifugo.Configure(cfg.Centrifugo)
+
+	if err := config.ConnectDB(cfg.DB); err != nil {
+		log.Fatal("❌ ", err)
+	}
+
+	// `misi-paket migrate ...` hanya mengurus skema lalu keluar
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrate(os.Args[2:])
+		config.CloseDB()
+		if err != nil {
+			log.Fatal("❌ ", err)
+		}
+		return
+	}
+
+	if cfg.DB.AutoMigrate {
+		if err := migrations.Up(config.DB); err != nil {
+			log.Fatal("❌ ", err)
+		}
+	}
+	permission.Load()
+
+	// RATE_LIMIT_STORE=postgres kalau server dijalankan lebih dari satu instance
+	if cfg.RateLimit == "postgres" {
+		ratelimit.SetStore(ratelimit.NewPostgresStore(config.DB))
+	}
+
+	// Worker background berhenti saat jobsCtx dibatalkan ketika shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobs.StartDocumentReminder(jobsCtx)
+
+	// Recovery ditangani middleware.ErrorHandler yang dipasang di SetupRoutes
+	r := gin.New()
+	// Tanpa ini gin percaya X-Forwarded-For dari siapa saja, sehingga rate
+	// limit dan throttle login per IP bisa diakali dengan header palsu
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("❌ TRUSTED_PROXIES tidak valid: ", err)
+	}
+	r.Use(middleware.Logger())
+
+	// Tanpa CORS_ORIGINS tidak ada header CORS, jadi browser menolak semua
+	// request lintas origin; aplikasi mobile tidak terpengaruh
+	if len(cfg.Server.CORSOrigins) > 0 {
+		r.Use(cors.New(cors.Config{
+			AllowOrigins:     cfg.Server.CORSOrigins,
+			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader},
+			ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"},
+			AllowCredentials: true, // hanya kalau butuh cookie/session
+		}))
+	}
+
+	// Repository Postgres → service → handler
+	services := service.New(
+		repository.NewPostgres(config.DB),
+		service.PublisherFunc(centrifugo.Publish),
+		auth.RevokeUserSessions,
+	)
+	route.SetupRoutes(r, services)
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.Server.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	go func() {
+		log.Println("🚀 Server jalan di", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("❌ Server berhenti: ", err)
+		}
+	}()
+
+	// Tunggu SIGTERM (deploy) atau Ctrl+C
+	sig, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	<-sig.Done()
+	log.Println("🛑 Menerima sinyal berhenti, menyelesaikan request yang berjalan...")
 
-	r := gin.Default()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
 
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true, // hanya kalau butuh cookie/session
-	}))
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("⚠️ Request belum selesai saat batas waktu shutdown:", err)
+	}
 
-	route.SetupRoutes(r)
+	stopJobs()
+	if err := jobs.Wait(ctx); err != nil {
+		log.Println("⚠️ Worker background belum berhenti:", err)
+	}
 
-	r.Run(":8080")
+	if err := config.CloseDB(); err != nil {
+		log.Println("⚠️ Gagal menutup koneksi database:", err)
+	}
+	log.Println("✅ Server berhenti dengan rapi")
 }
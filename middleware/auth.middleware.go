@@ -1,74 +1,78 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
 	"github.com/mubarok-ridho/misi-paket.backend/utils"
 )
 
+// sessionValidator memeriksa session di access token (default: tabel sessions)
+var sessionValidator = auth.ValidateSession
+
+// SetSessionValidator mengganti pemeriksaan session, mis. untuk test route
+// tanpa database
+func SetSessionValidator(fn func(claims *utils.JWTClaims, ip string) error) {
+	sessionValidator = fn
+}
+
+// JWTAuthMiddleware adalah satu-satunya middleware autentikasi: memverifikasi
+// access token lalu menyimpan userID, role dan sessionID di context
 func JWTAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token tidak ditemukan"})
+			c.Error(apperror.TokenMissing)
+			c.Abort()
 			return
 		}
 
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Format token salah"})
+		tokenParts := strings.Fields(authHeader)
+		if len(tokenParts) != 2 || !strings.EqualFold(tokenParts[0], "Bearer") {
+			c.Error(apperror.TokenMalformed)
+			c.Abort()
 			return
 		}
 
 		claims, err := utils.ParseToken(tokenParts[1])
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid"})
+			c.Error(apperror.TokenInvalid)
+			c.Abort()
+			return
+		}
+
+		// Token ditolak kalau session sudah logout/dicabut atau user dinonaktifkan
+		if err := sessionValidator(claims, c.ClientIP()); err != nil {
+			if errors.Is(err, auth.ErrSessionInvalid) || errors.Is(err, auth.ErrUserInactive) {
+				c.Error(apperror.SessionInvalid)
+			} else {
+				// Session tidak bisa diperiksa (mis. database down): bukan salah token
+				c.Error(apperror.Unavailable.Wrap(err))
+			}
+			c.Abort()
 			return
 		}
 
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.SessionID)
 		c.Next()
 	}
 }
 
-func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		for _, r := range allowedRoles {
-			if role == r {
-				c.Next()
-				return
-			}
-		}
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Akses ditolak, role tidak sesuai"})
-	}
-}
-
-// Middleware yang memverifikasi token dan inject user info ke context
-func AuthMiddleware() gin.HandlerFunc {
+// RequirePermission meloloskan request kalau role user punya permission ini.
+// Pemetaan role → permission diatur lewat package permission.
+func RequirePermission(perm string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak ditemukan"})
-			c.Abort()
+		if permission.Has(c.GetString("role"), perm) {
+			c.Next()
 			return
 		}
-
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := utils.ParseToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid"})
-			c.Abort()
-			return
-		}
-
-		// Simpan user info di context
-		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
-
-		c.Next()
+		c.Error(apperror.PermissionDenied.With("permission", perm))
+		c.Abort()
 	}
 }
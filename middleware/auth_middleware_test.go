@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// Hanya session yang memang tidak valid dijawab 401; gangguan saat memeriksa
+// session dijawab 503 supaya client tidak membuang token-nya
+func TestJWTAuthSessionErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if err := utils.LoadKeys(utils.KeyConfig{Algorithm: "HS256", KeyID: "test", Secret: "test-secret-yang-cukup-panjang-untuk-hs256"}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	token, err := utils.GenerateToken(1, "customer", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetSessionValidator(auth.ValidateSession) })
+
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"valid", nil, http.StatusOK},
+		{"revoked or expired", auth.ErrSessionInvalid, http.StatusUnauthorized},
+		{"user inactive", auth.ErrUserInactive, http.StatusUnauthorized},
+		{"database down", fmt.Errorf("baca session 1: %w", errors.New("connection refused")), http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetSessionValidator(func(*utils.JWTClaims, string) error { return tc.err })
+
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.GET("/me", JWTAuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.want {
+				t.Fatalf("status %d, want %d: %s", w.Code, tc.want, w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+)
+
+// ErrorHandler menulis satu bentuk response untuk semua error:
+//
+//	{"error": "pesan", "code": "order_not_found", "request_id": "...", "fields": {...}}
+//
+// Pesan diambil dari katalog i18n sesuai bahasa request.
+// Handler cukup c.Error(apperror.X) lalu return. Error yang bukan
+// *apperror.Error dan panic dijawab 500 tanpa membocorkan detailnya.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if p := recover(); p != nil {
+				// Handler sengaja memutus koneksi (mis. export terpotong)
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				c.Error(apperror.Internal.Wrap(fmt.Errorf("panic: %v", p)))
+				c.Abort()
+				respondError(c)
+			}
+		}()
+
+		c.Next()
+		respondError(c)
+	}
+}
+
+func respondError(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+
+	e := apperror.From(c.Errors.Last().Err)
+	if e.Server() {
+		Logf(c, "❌ %s %s: %v", c.Request.Method, c.Request.URL.Path, e)
+	}
+
+	lang := i18n.FromContext(c)
+	body := gin.H{
+		"error":      e.Text(lang),
+		"code":       e.Code,
+		"request_id": RequestIDFrom(c),
+	}
+	if fields := e.FieldMessages(lang); len(fields) > 0 {
+		body["fields"] = fields
+	}
+	c.JSON(e.Status, body)
+}
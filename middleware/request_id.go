@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// ID dari client (mis. load balancer) dipakai kalau bentuknya wajar
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID memberi setiap request ID yang ikut di header response,
+// body error dan baris log
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// RequestIDFrom ID request yang sedang diproses, "-" kalau tidak ada
+func RequestIDFrom(c *gin.Context) string {
+	if id := c.GetString("requestID"); id != "" {
+		return id
+	}
+	return "-"
+}
+
+// Logger access log gin dengan request ID
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		return fmt.Sprintf("[GIN] %s | %3d | %13v | %15s | %-7s %s | rid=%s\n",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			p.StatusCode,
+			p.Latency,
+			p.ClientIP,
+			p.Method,
+			p.Path,
+			p.Keys["requestID"],
+		)
+	})
+}
+
+// Logf log.Printf dengan request ID di depan
+func Logf(c *gin.Context, format string, args ...interface{}) {
+	log.Printf("[rid=%s] "+format, append([]interface{}{RequestIDFrom(c)}, args...)...)
+}
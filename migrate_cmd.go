@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/migrations"
+)
+
+const migrateUsage = `pemakaian: misi-paket migrate <perintah>
+
+  up        terapkan semua migrasi yang belum jalan
+  down [n]  batalkan n migrasi terakhir (default 1)
+  status    tampilkan versi yang sudah/belum diterapkan`
+
+// runMigrate menjalankan subcommand `migrate`
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		return migrations.Up(config.DB)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("jumlah langkah tidak valid: %q", args[1])
+			}
+			steps = n
+		}
+		return migrations.Down(config.DB, steps)
+	case "status":
+		list, err := migrations.List(config.DB)
+		if err != nil {
+			return err
+		}
+		for _, m := range list {
+			applied := "belum"
+			if m.AppliedAt != nil {
+				applied = m.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-20s  %s\n", m.Version, m.Name, applied)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
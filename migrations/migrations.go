@@ -0,0 +1,252 @@
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// File migrasi: sql/<versi>_<nama>.up.sql dan sql/<versi>_<nama>.down.sql.
+// Versi tidak boleh diubah setelah dirilis; perubahan skema selalu lewat
+// file baru dengan versi lebih besar.
+//
+//go:embed sql/*.sql
+var files embed.FS
+
+// Kunci advisory supaya dua instance tidak menjalankan migrasi bersamaan
+const advisoryLockID = 740501
+
+// Migration adalah satu versi skema
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah migrasi beserta waktu diterapkan (nil kalau belum)
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "public.schema_migrations"
+}
+
+// All membaca semua migrasi yang di-embed, terurut naik
+func All() ([]Migration, error) {
+	return load(files, "sql")
+}
+
+// load membaca pasangan file up/down di dir lalu mengurutkannya per versi
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		name := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", name)
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+		rawVersion, label, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("nama file migrasi tidak valid: %s", name)
+		}
+
+		body, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("versi %d dipakai dua nama: %s dan %s", version, m.Name, label)
+		}
+		// mis. 0001_init.up.sql dan 1_init.up.sql: versi sama, file ganda
+		target := &m.Up
+		if direction == "down" {
+			target = &m.Down
+		}
+		if *target != "" {
+			return nil, fmt.Errorf("versi %d punya lebih dari satu file %s", version, direction)
+		}
+		*target = string(body)
+	}
+
+	all := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migrasi %d_%s harus punya file up dan down", m.Version, m.Name)
+		}
+		all = append(all, *m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+// Up menerapkan semua migrasi yang belum jalan. Tiap migrasi berjalan
+// dalam transaksinya sendiri bersama pencatatan versinya.
+func Up(db *gorm.DB) error {
+	return withLock(db, func(conn *gorm.DB) error {
+		pending, err := pendingMigrations(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range pending {
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(m.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migrasi %d_%s gagal: %w", m.Version, m.Name, err)
+			}
+			log.Printf("⬆️ Migrasi %d_%s diterapkan", m.Version, m.Name)
+		}
+		return nil
+	})
+}
+
+// Down membatalkan sejumlah migrasi terakhir
+func Down(db *gorm.DB, steps int) error {
+	all, err := All()
+	if err != nil {
+		return err
+	}
+	byVersion := map[int64]Migration{}
+	for _, m := range all {
+		byVersion[m.Version] = m
+	}
+
+	return withLock(db, func(conn *gorm.DB) error {
+		var applied []schemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return err
+		}
+
+		for _, a := range applied {
+			m, ok := byVersion[a.Version]
+			if !ok {
+				return fmt.Errorf("migrasi %d_%s tidak ada di binary ini", a.Version, a.Name)
+			}
+
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(m.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, a.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback %d_%s gagal: %w", m.Version, m.Name, err)
+			}
+			log.Printf("⬇️ Migrasi %d_%s dibatalkan", m.Version, m.Name)
+		}
+		return nil
+	})
+}
+
+// List mengembalikan status semua migrasi
+func List(db *gorm.DB) ([]Status, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Status, 0, len(all))
+	for _, m := range all {
+		s := Status{Migration: m}
+		if a, ok := applied[m.Version]; ok {
+			at := a.AppliedAt
+			s.AppliedAt = &at
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func pendingMigrations(db *gorm.DB) ([]Migration, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range all {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func appliedVersions(db *gorm.DB) (map[int64]schemaMigration, error) {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS public.schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int64]schemaMigration, len(rows))
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+// withLock menjalankan fn di satu koneksi yang memegang advisory lock
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", advisoryLockID).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", advisoryLockID)
+
+		return fn(conn)
+	})
+}
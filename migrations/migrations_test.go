@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func sqlFiles(names ...string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for _, name := range names {
+		fsys["sql/"+name] = &fstest.MapFile{Data: []byte("-- " + name)}
+	}
+	return fsys
+}
+
+func TestLoadSortsByVersion(t *testing.T) {
+	fsys := sqlFiles(
+		"10_add_index.up.sql", "10_add_index.down.sql",
+		"2_orders.up.sql", "2_orders.down.sql",
+		"1_init.up.sql", "1_init.down.sql",
+	)
+
+	all, err := load(fsys, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, m := range all {
+		got = append(got, m.Name)
+	}
+	if strings.Join(got, ",") != "init,orders,add_index" {
+		t.Fatalf("order %v, want numeric version order", got)
+	}
+	if all[1].Up != "-- 2_orders.up.sql" || all[1].Down != "-- 2_orders.down.sql" {
+		t.Fatalf("migration 2 up %q down %q", all[1].Up, all[1].Down)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		files []string
+		want  string
+	}{
+		{"missing down", []string{"1_init.up.sql"}, "harus punya file up dan down"},
+		{"missing up", []string{"1_init.down.sql"}, "harus punya file up dan down"},
+		{"version with two names", []string{"1_init.up.sql", "1_awal.down.sql"}, "dipakai dua nama"},
+		{"same version twice", []string{"0001_init.up.sql", "1_init.up.sql", "1_init.down.sql"}, "lebih dari satu file up"},
+		{"no direction", []string{"1_init.sql"}, "tidak valid"},
+		{"no label", []string{"1.up.sql", "1.down.sql"}, "tidak valid"},
+		{"non-numeric version", []string{"v1_init.up.sql", "v1_init.down.sql"}, "tidak valid"},
+		{"not sql", []string{"README.md"}, "tidak valid"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := load(sqlFiles(tc.files...), "sql")
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+// Migrasi yang di-embed harus selalu lolos aturan di atas
+func TestAllEmbedded(t *testing.T) {
+	all, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i].Version <= all[i-1].Version {
+			t.Fatalf("version %d after %d", all[i].Version, all[i-1].Version)
+		}
+	}
+}
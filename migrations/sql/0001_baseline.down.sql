@@ -0,0 +1,3 @@
+-- Baseline sengaja tidak di-rollback: tabel users, orders dan messages
+-- berisi data produksi yang sudah ada sebelum sistem migrasi.
+SELECT 1;
@@ -0,0 +1,62 @@
+-- Skema inti yang sebelumnya dibuat manual di luar repo. Semua perintah
+-- memakai IF NOT EXISTS supaya aman dijalankan di database yang sudah ada.
+
+CREATE TABLE IF NOT EXISTS public.users (
+    id           BIGSERIAL PRIMARY KEY,
+    name         TEXT,
+    email        TEXT,
+    password     TEXT,
+    role         TEXT,
+    phone        TEXT,
+    kendaraan    TEXT,
+    status       TEXT,
+    plat_nomor   TEXT,
+    status_kerja VARCHAR(10) DEFAULT 'aktif'
+);
+
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS kendaraan TEXT;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS plat_nomor TEXT;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS status_kerja VARCHAR(10) DEFAULT 'aktif';
+
+-- Email unik; lewati kalau sudah ada unique constraint/index bawaan skema lama
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_index i
+        JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attname = 'email'
+        WHERE i.indrelid = 'public.users'::regclass AND i.indisunique AND i.indkey::int2[] = ARRAY[a.attnum]
+    ) THEN
+        CREATE UNIQUE INDEX uni_users_email ON public.users (email);
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS public.orders (
+    id             BIGSERIAL PRIMARY KEY,
+    customer_id    BIGINT,
+    kurir_id       BIGINT,
+    metode_bayar   TEXT,
+    status         TEXT,
+    layanan        TEXT,
+    nominal        BIGINT,
+    payment_status TEXT,
+    created_at     TIMESTAMPTZ,
+    updated_at     TIMESTAMPTZ,
+    deleted_at     TIMESTAMPTZ
+);
+
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS metode_bayar TEXT;
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS nominal BIGINT;
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS payment_status TEXT;
+ALTER TABLE public.orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_public_orders_deleted_at ON public.orders (deleted_at);
+
+CREATE TABLE IF NOT EXISTS public.messages (
+    id          BIGSERIAL PRIMARY KEY,
+    order_id    BIGINT,
+    sender_id   BIGINT,
+    receiver_id BIGINT,
+    content     TEXT,
+    sent_at     TIMESTAMPTZ,
+    is_read     BOOLEAN DEFAULT FALSE
+);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.password_histories;
+DROP TABLE IF EXISTS public.password_resets;
+DROP TABLE IF EXISTS public.otp_codes;
+DROP TABLE IF EXISTS public.sessions;
+DROP INDEX IF EXISTS public.idx_users_phone_unique;
+ALTER TABLE public.users DROP COLUMN IF EXISTS phone_verified_at;
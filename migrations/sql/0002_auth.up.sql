@@ -0,0 +1,55 @@
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ;
+
+-- Kalau data lama masih punya nomor HP duplikat, bersihkan dulu sebelum migrasi
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_phone_unique ON public.users (phone) WHERE phone <> '';
+
+CREATE TABLE IF NOT EXISTS public.sessions (
+    id                  BIGSERIAL PRIMARY KEY,
+    user_id             BIGINT NOT NULL,
+    refresh_token_hash  CHAR(64),
+    previous_token_hash CHAR(64),
+    device_name         VARCHAR(100),
+    platform            VARCHAR(30),
+    ip                  VARCHAR(45),
+    user_agent          VARCHAR(255),
+    last_seen_at        TIMESTAMPTZ,
+    expires_at          TIMESTAMPTZ,
+    revoked_at          TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ,
+    updated_at          TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_public_sessions_user_id ON public.sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_public_sessions_refresh_token_hash ON public.sessions (refresh_token_hash);
+CREATE INDEX IF NOT EXISTS idx_public_sessions_previous_token_hash ON public.sessions (previous_token_hash);
+
+CREATE TABLE IF NOT EXISTS public.otp_codes (
+    id          BIGSERIAL PRIMARY KEY,
+    phone       VARCHAR(20),
+    purpose     VARCHAR(20),
+    code_hash   CHAR(64),
+    attempts    BIGINT,
+    expires_at  TIMESTAMPTZ,
+    consumed_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_otp_phone_purpose ON public.otp_codes (phone, purpose);
+
+CREATE TABLE IF NOT EXISTS public.password_resets (
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT,
+    token_hash   CHAR(64),
+    requested_by BIGINT,
+    expires_at   TIMESTAMPTZ,
+    used_at      TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_public_password_resets_user_id ON public.password_resets (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_public_password_resets_token_hash ON public.password_resets (token_hash);
+
+CREATE TABLE IF NOT EXISTS public.password_histories (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT,
+    password_hash TEXT,
+    created_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_public_password_histories_user_id ON public.password_histories (user_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.kurir_documents;
+DROP TABLE IF EXISTS public.kurir_applications;
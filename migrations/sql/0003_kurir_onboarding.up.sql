@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS public.kurir_applications (
+    id            BIGSERIAL PRIMARY KEY,
+    user_id       BIGINT,
+    status        VARCHAR(10) DEFAULT 'pending',
+    reject_reason TEXT,
+    reviewed_by   BIGINT,
+    reviewed_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_public_kurir_applications_user_id ON public.kurir_applications (user_id);
+CREATE INDEX IF NOT EXISTS idx_public_kurir_applications_status ON public.kurir_applications (status);
+
+CREATE TABLE IF NOT EXISTS public.kurir_documents (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT,
+    type             VARCHAR(20),
+    file_path        TEXT,
+    content_type     VARCHAR(50),
+    expires_at       TIMESTAMPTZ,
+    reminder_sent_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_public_kurir_documents_user_id ON public.kurir_documents (user_id);
+CREATE INDEX IF NOT EXISTS idx_public_kurir_documents_expires_at ON public.kurir_documents (expires_at);
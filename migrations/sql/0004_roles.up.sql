@@ -0,0 +1,14 @@
+-- Role bawaan & permission default diisi aplikasi saat start (permission.Load)
+CREATE TABLE IF NOT EXISTS public.roles (
+    name        VARCHAR(30) PRIMARY KEY,
+    description TEXT,
+    built_in    BOOLEAN,
+    created_at  TIMESTAMPTZ,
+    updated_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS public.role_permissions (
+    role       VARCHAR(30),
+    permission VARCHAR(50),
+    PRIMARY KEY (role, permission)
+);
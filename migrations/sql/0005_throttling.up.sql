@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS public.login_throttles (
+    id              BIGSERIAL PRIMARY KEY,
+    scope           VARCHAR(20),
+    value           VARCHAR(255),
+    failures        BIGINT,
+    lockouts        BIGINT,
+    last_failure_at TIMESTAMPTZ,
+    locked_until    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_throttle_key ON public.login_throttles (scope, value);
+CREATE INDEX IF NOT EXISTS idx_public_login_throttles_locked_until ON public.login_throttles (locked_until);
+
+CREATE TABLE IF NOT EXISTS public.rate_limit_buckets (
+    key        VARCHAR(255) PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_public_rate_limit_buckets_updated_at ON public.rate_limit_buckets (updated_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.idx_messages_order_sent_at;
+DROP INDEX IF EXISTS public.idx_orders_status_updated_at;
+DROP INDEX IF EXISTS public.idx_orders_customer_id;
+DROP INDEX IF EXISTS public.idx_orders_kurir_status;
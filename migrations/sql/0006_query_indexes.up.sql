@@ -0,0 +1,8 @@
+-- Daftar pesanan kurir (GetOrdersForKurir, GetOrdersProses, kurir tersedia)
+CREATE INDEX IF NOT EXISTS idx_orders_kurir_status ON public.orders (kurir_id, status);
+-- Pesanan milik customer (GetMyOrders)
+CREATE INDEX IF NOT EXISTS idx_orders_customer_id ON public.orders (customer_id);
+-- Laporan pendapatan / pesanan selesai per hari
+CREATE INDEX IF NOT EXISTS idx_orders_status_updated_at ON public.orders (status, updated_at);
+-- Riwayat chat per pesanan
+CREATE INDEX IF NOT EXISTS idx_messages_order_sent_at ON public.messages (order_id, sent_at);
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// KurirApplication adalah pengajuan calon kurir yang menunggu review admin
+type KurirApplication struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	UserID       uint       `gorm:"uniqueIndex" json:"user_id"`
+	User         User       `gorm:"foreignKey:UserID" json:"-"`
+	Status       string     `gorm:"size:10;index;default:'pending'" json:"status"` // pending, approved, rejected
+	RejectReason *string    `json:"reject_reason"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (KurirApplication) TableName() string {
+	return "public.kurir_applications"
+}
+
+// KurirDocument adalah satu berkas onboarding kurir (KTP, SIM, STNK, foto kendaraan)
+type KurirDocument struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	UserID         uint       `gorm:"index" json:"user_id"`
+	Type           string     `gorm:"size:20" json:"type"` // ktp, sim, stnk, foto_kendaraan
+	FilePath       string     `json:"-"`
+	ContentType    string     `gorm:"size:50" json:"content_type"`
+	ExpiresAt      *time.Time `gorm:"index" json:"expires_at"` // SIM & STNK punya masa berlaku
+	ReminderSentAt *time.Time `json:"reminder_sent_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (KurirDocument) TableName() string {
+	return "public.kurir_documents"
+}
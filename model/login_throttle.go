@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// LoginThrottle mencatat kegagalan login per identifier (email/HP) atau per IP
+type LoginThrottle struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Scope         string     `gorm:"size:20;uniqueIndex:idx_login_throttle_key" json:"scope"` // identifier, ip
+	Value         string     `gorm:"size:255;uniqueIndex:idx_login_throttle_key" json:"value"`
+	Failures      int        `json:"failures"`
+	Lockouts      int        `json:"lockouts"` // berapa kali terkunci berturut-turut, untuk durasi progresif
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"index" json:"locked_until"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (LoginThrottle) TableName() string {
+	return "public.login_throttles"
+}
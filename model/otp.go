@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// OTPCode menyimpan kode OTP dalam bentuk hash, tidak pernah plaintext
+type OTPCode struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Phone      string     `gorm:"size:20;index:idx_otp_phone_purpose" json:"phone"`
+	Purpose    string     `gorm:"size:20;index:idx_otp_phone_purpose" json:"purpose"` // login, verify_phone, reset_password
+	CodeHash   string     `gorm:"type:char(64)" json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (OTPCode) TableName() string {
+	return "public.otp_codes"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// PasswordHistory menyimpan hash password lama untuk mencegah dipakai ulang
+type PasswordHistory struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"index" json:"user_id"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (PasswordHistory) TableName() string {
+	return "public.password_histories"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// PasswordReset adalah token reset sekali pakai (disimpan dalam bentuk hash)
+type PasswordReset struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index" json:"user_id"`
+	TokenHash   string     `gorm:"type:char(64);uniqueIndex" json:"-"`
+	RequestedBy *uint      `json:"requested_by"` // admin yang memicu reset, nil kalau user sendiri
+	ExpiresAt   time.Time  `json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (PasswordReset) TableName() string {
+	return "public.password_resets"
+}
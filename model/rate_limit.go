@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// RateLimitBucket adalah state token bucket untuk ratelimit.PostgresStore
+type RateLimitBucket struct {
+	Key       string    `gorm:"primaryKey;size:255"`
+	Tokens    float64   `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"index;autoUpdateTime:false"`
+}
+
+func (RateLimitBucket) TableName() string {
+	return "public.rate_limit_buckets"
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Role adalah nama peran yang bisa diberikan ke user (admin, kurir, customer, finance, ...)
+type Role struct {
+	Name        string    `gorm:"primaryKey;size:30" json:"name"`
+	Description string    `json:"description"`
+	BuiltIn     bool      `json:"built_in"` // role bawaan tidak bisa dihapus
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Role) TableName() string {
+	return "public.roles"
+}
+
+// RolePermission memetakan role ke satu permission bernama (mis. orders.read_all)
+type RolePermission struct {
+	Role       string `gorm:"primaryKey;size:30" json:"role"`
+	Permission string `gorm:"primaryKey;size:50" json:"permission"`
+}
+
+func (RolePermission) TableName() string {
+	return "public.role_permissions"
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Session mewakili satu login (satu device). Refresh token disimpan
+// dalam bentuk hash dan dirotasi setiap kali dipakai.
+type Session struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	UserID            uint       `gorm:"index" json:"user_id"`
+	User              User       `gorm:"foreignKey:UserID" json:"-"`
+	RefreshTokenHash  string     `gorm:"type:char(64);uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"type:char(64);index" json:"-"` // untuk deteksi refresh token dipakai ulang
+	DeviceName        string     `gorm:"size:100" json:"device_name"`
+	Platform          string     `gorm:"size:30" json:"platform"` // android, ios, web
+	IP                string     `gorm:"size:45" json:"ip"`
+	UserAgent         string     `gorm:"size:255" json:"user_agent"`
+	LastSeenAt        time.Time  `json:"last_seen_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	RevokedAt         *time.Time `json:"revoked_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (Session) TableName() string {
+	return "public.sessions"
+}
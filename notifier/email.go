@@ -0,0 +1,31 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// EmailSender mengirim email (SMTP, layanan transactional mail, dll)
+type EmailSender interface {
+	SendEmail(ctx context.Context, to, subject, body string) error
+}
+
+// LogEmailSender hanya menulis email ke log, untuk development
+type LogEmailSender struct{}
+
+func (LogEmailSender) SendEmail(_ context.Context, to, subject, body string) error {
+	log.Printf("📧 [dev] email ke %s — %s\n%s", to, subject, body)
+	return nil
+}
+
+var emailSender EmailSender = LogEmailSender{}
+
+// SetEmailSender mengganti email sender default (LogEmailSender)
+func SetEmailSender(s EmailSender) {
+	emailSender = s
+}
+
+// SendEmail mengirim lewat email sender yang sedang terpasang
+func SendEmail(ctx context.Context, to, subject, body string) error {
+	return emailSender.SendEmail(ctx, to, subject, body)
+}
@@ -0,0 +1,33 @@
+package notifier
+
+import (
+	"context"
+	"log"
+)
+
+// Sender mengirim pesan singkat ke nomor HP (SMS, WhatsApp, dll).
+// Implementasi gateway tinggal memenuhi interface ini lalu dipasang
+// lewat SetSender saat startup.
+type Sender interface {
+	Send(ctx context.Context, phone, message string) error
+}
+
+// LogSender hanya menulis pesan ke log, untuk development
+type LogSender struct{}
+
+func (LogSender) Send(_ context.Context, phone, message string) error {
+	log.Printf("📨 [dev] pesan ke %s: %s", phone, message)
+	return nil
+}
+
+var sender Sender = LogSender{}
+
+// SetSender mengganti sender default (LogSender)
+func SetSender(s Sender) {
+	sender = s
+}
+
+// Send mengirim lewat sender yang sedang terpasang
+func Send(ctx context.Context, phone, message string) error {
+	return sender.Send(ctx, phone, message)
+}
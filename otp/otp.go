@@ -0,0 +1,139 @@
+package otp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/notifier"
+)
+
+// Tujuan OTP, kode untuk satu tujuan tidak bisa dipakai untuk tujuan lain
+const (
+	PurposeLogin         = "login"
+	PurposeVerifyPhone   = "verify_phone"
+	PurposeResetPassword = "reset_password"
+)
+
+const (
+	codeDigits     = 6
+	codeTTL        = 5 * time.Minute
+	maxAttempts    = 5
+	resendInterval = time.Minute
+	maxPerHour     = 5
+)
+
+var (
+	ErrTooSoon     = errors.New("tunggu sebentar sebelum meminta kode baru")
+	ErrTooMany     = errors.New("terlalu banyak permintaan kode, coba lagi nanti")
+	ErrInvalidCode = errors.New("kode OTP salah atau sudah kedaluwarsa")
+	ErrNoAttempts  = errors.New("kode OTP sudah terlalu sering salah, minta kode baru")
+)
+
+// Issue membuat kode baru untuk nomor + tujuan lalu mengirimnya lewat notifier.
+// Dibatasi 1 kode per menit dan maksimal 5 kode per jam per nomor.
+func Issue(ctx context.Context, phone, purpose string) error {
+	last, err := store.Latest(phone, purpose)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(last.CreatedAt) < resendInterval {
+		return ErrTooSoon
+	}
+
+	lastHour, err := store.CountSince(phone, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if lastHour >= maxPerHour {
+		return ErrTooMany
+	}
+
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+
+	// Kode lama untuk tujuan yang sama langsung tidak berlaku
+	record := model.OTPCode{
+		Phone:     phone,
+		Purpose:   purpose,
+		CodeHash:  hashCode(phone, purpose, code),
+		ExpiresAt: time.Now().Add(codeTTL),
+	}
+	if err := store.Replace(&record); err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Kode OTP FaiExpress: %s. Berlaku %d menit. Jangan berikan kode ini ke siapa pun.",
+		code, int(codeTTL.Minutes()))
+	return notifier.Send(ctx, phone, msg)
+}
+
+// Verify mencocokkan kode terbaru untuk nomor + tujuan. Kode hanya bisa
+// dipakai sekali dan hangus setelah 5 kali salah.
+func Verify(phone, purpose, code string) error {
+	record, err := store.Active(phone, purpose, time.Now())
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrInvalidCode
+	}
+
+	if record.Attempts >= maxAttempts {
+		return ErrNoAttempts
+	}
+
+	expected := hashCode(phone, purpose, code)
+	if !hmac.Equal([]byte(expected), []byte(record.CodeHash)) {
+		// Ditambah di database, bukan dari nilai yang dibaca di atas, supaya
+		// tebakan paralel tetap terhitung satu per satu
+		attempts, ok, err := store.AddAttempt(record.ID, maxAttempts)
+		if err != nil {
+			return err
+		}
+		if !ok || attempts >= maxAttempts {
+			return ErrNoAttempts
+		}
+		return ErrInvalidCode
+	}
+
+	// Update bersyarat supaya kode tidak bisa dipakai dua kali bersamaan
+	// atau setelah percobaannya habis oleh tebakan paralel
+	ok, err := store.Consume(record.ID, maxAttempts, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+func generateCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < codeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeDigits, n), nil
+}
+
+// hashCode memakai HMAC supaya kode 6 digit tidak bisa di-brute force dari isi DB
+func hashCode(phone, purpose, code string) string {
+	mac := hmac.New(sha256.New, []byte(config.Get().OTPSecret))
+	mac.Write([]byte(phone + ":" + purpose + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,207 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/notifier"
+)
+
+const phone = "081234567890"
+
+// inbox menangkap pesan OTP yang dikirim lewat notifier
+type inbox struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (i *inbox) Send(_ context.Context, _, message string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.messages = append(i.messages, message)
+	return nil
+}
+
+var codePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// lastCode kode 6 digit dari pesan terakhir
+func (i *inbox) lastCode(t *testing.T) string {
+	t.Helper()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if len(i.messages) == 0 {
+		t.Fatal("no OTP sent")
+	}
+	code := codePattern.FindString(i.messages[len(i.messages)-1])
+	if code == "" {
+		t.Fatalf("no code in %q", i.messages[len(i.messages)-1])
+	}
+	return code
+}
+
+func setup(t *testing.T) (*MemoryStore, *inbox) {
+	t.Helper()
+	s, in := NewMemoryStore(), &inbox{}
+	SetStore(s)
+	notifier.SetSender(in)
+	t.Cleanup(func() {
+		SetStore(postgresStore{})
+		notifier.SetSender(notifier.LogSender{})
+	})
+	return s, in
+}
+
+// age memundurkan waktu semua kode, seolah dibuat d yang lalu
+func (s *MemoryStore) age(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.codes {
+		c.CreatedAt = c.CreatedAt.Add(-d)
+		c.ExpiresAt = c.ExpiresAt.Add(-d)
+	}
+}
+
+// wrongCode kode 6 digit yang pasti bukan code
+func wrongCode(code string) string {
+	if code == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	_, in := setup(t)
+
+	if err := Issue(context.Background(), phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	code := in.lastCode(t)
+
+	if err := Verify(phone, PurposeVerifyPhone, code); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("other purpose: %v, want ErrInvalidCode", err)
+	}
+	if err := Verify("089999999999", PurposeLogin, code); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("other phone: %v, want ErrInvalidCode", err)
+	}
+	if err := Verify(phone, PurposeLogin, code); err != nil {
+		t.Fatalf("valid code: %v", err)
+	}
+	if err := Verify(phone, PurposeLogin, code); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("reused code: %v, want ErrInvalidCode", err)
+	}
+}
+
+func TestIssueThrottle(t *testing.T) {
+	s, in := setup(t)
+	ctx := context.Background()
+
+	if err := Issue(ctx, phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	first := in.lastCode(t)
+	if err := Issue(ctx, phone, PurposeLogin); !errors.Is(err, ErrTooSoon) {
+		t.Fatalf("resend within interval: %v, want ErrTooSoon", err)
+	}
+
+	// Kode baru membatalkan kode lama untuk tujuan yang sama
+	s.age(resendInterval)
+	if err := Issue(ctx, phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(phone, PurposeLogin, first); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("replaced code: %v, want ErrInvalidCode", err)
+	}
+
+	for i := 2; i < maxPerHour; i++ {
+		s.age(resendInterval)
+		if err := Issue(ctx, phone, PurposeLogin); err != nil {
+			t.Fatalf("code %d: %v", i+1, err)
+		}
+	}
+	s.age(resendInterval)
+	if err := Issue(ctx, phone, PurposeLogin); !errors.Is(err, ErrTooMany) {
+		t.Fatalf("code %d within an hour: %v, want ErrTooMany", maxPerHour+1, err)
+	}
+
+	s.age(time.Hour)
+	if err := Issue(ctx, phone, PurposeLogin); err != nil {
+		t.Fatalf("after an hour: %v", err)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	s, in := setup(t)
+
+	if err := Issue(context.Background(), phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	s.age(codeTTL)
+	if err := Verify(phone, PurposeLogin, in.lastCode(t)); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("expired code: %v, want ErrInvalidCode", err)
+	}
+}
+
+func TestVerifyLockout(t *testing.T) {
+	_, in := setup(t)
+
+	if err := Issue(context.Background(), phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	code := in.lastCode(t)
+
+	for i := 1; i <= maxAttempts; i++ {
+		want := ErrInvalidCode
+		if i == maxAttempts {
+			want = ErrNoAttempts
+		}
+		if err := Verify(phone, PurposeLogin, wrongCode(code)); !errors.Is(err, want) {
+			t.Fatalf("wrong guess %d: %v, want %v", i, err, want)
+		}
+	}
+	if err := Verify(phone, PurposeLogin, code); !errors.Is(err, ErrNoAttempts) {
+		t.Fatalf("right code after lockout: %v, want ErrNoAttempts", err)
+	}
+}
+
+// Tebakan salah yang datang bersamaan tidak boleh melewati maxAttempts
+func TestVerifyLockoutConcurrent(t *testing.T) {
+	s, in := setup(t)
+
+	if err := Issue(context.Background(), phone, PurposeLogin); err != nil {
+		t.Fatal(err)
+	}
+	code := in.lastCode(t)
+
+	const guesses = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	invalid := 0
+	for i := 0; i < guesses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := Verify(phone, PurposeLogin, wrongCode(code))
+			if errors.Is(err, ErrInvalidCode) {
+				mu.Lock()
+				invalid++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if invalid != maxAttempts-1 {
+		t.Fatalf("%d guesses answered ErrInvalidCode, want %d", invalid, maxAttempts-1)
+	}
+	record, _ := s.Latest(phone, PurposeLogin)
+	if record.Attempts != maxAttempts {
+		t.Fatalf("attempts %d, want %d", record.Attempts, maxAttempts)
+	}
+	if err := Verify(phone, PurposeLogin, code); !errors.Is(err, ErrNoAttempts) {
+		t.Fatalf("right code after lockout: %v, want ErrNoAttempts", err)
+	}
+}
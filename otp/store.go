@@ -0,0 +1,215 @@
+package otp
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store menyimpan kode OTP. Default-nya tabel otp_codes lewat config.DB;
+// test memakai MemoryStore lewat SetStore.
+type Store interface {
+	// Latest kode terbaru untuk nomor + tujuan, termasuk yang sudah dipakai.
+	// nil kalau belum pernah ada.
+	Latest(phone, purpose string) (*model.OTPCode, error)
+	// CountSince jumlah kode untuk nomor ini (semua tujuan) sejak waktu tertentu
+	CountSince(phone string, since time.Time) (int64, error)
+	// Replace membatalkan kode aktif nomor + tujuan lalu menyimpan kode baru
+	Replace(record *model.OTPCode) error
+	// Active kode terbaru yang belum dipakai dan belum kedaluwarsa, nil kalau tidak ada
+	Active(phone, purpose string, now time.Time) (*model.OTPCode, error)
+	// AddAttempt menambah hitungan salah dalam satu langkah atomik selama
+	// masih di bawah max. ok false berarti percobaan sudah habis.
+	AddAttempt(id uint, max int) (attempts int, ok bool, err error)
+	// Consume menandai kode terpakai kalau belum dipakai dan percobaannya
+	// belum habis; false kalau kalah cepat dengan request lain
+	Consume(id uint, max int, now time.Time) (bool, error)
+}
+
+var store Store = postgresStore{}
+
+// SetStore mengganti store default (tabel otp_codes)
+func SetStore(s Store) {
+	store = s
+}
+
+// postgresStore membaca config.DB saat dipanggil karena koneksi baru dibuka
+// setelah package ini di-init
+type postgresStore struct{}
+
+func (postgresStore) Latest(phone, purpose string) (*model.OTPCode, error) {
+	var record model.OTPCode
+	err := config.DB.Where("phone = ? AND purpose = ?", phone, purpose).
+		Order("created_at DESC").First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (postgresStore) CountSince(phone string, since time.Time) (int64, error) {
+	var n int64
+	err := config.DB.Model(&model.OTPCode{}).
+		Where("phone = ? AND created_at > ?", phone, since).
+		Count(&n).Error
+	return n, err
+}
+
+func (postgresStore) Replace(record *model.OTPCode) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.OTPCode{}).
+			Where("phone = ? AND purpose = ? AND consumed_at IS NULL", record.Phone, record.Purpose).
+			Update("consumed_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+}
+
+func (postgresStore) Active(phone, purpose string, now time.Time) (*model.OTPCode, error) {
+	var record model.OTPCode
+	err := config.DB.
+		Where("phone = ? AND purpose = ? AND consumed_at IS NULL AND expires_at > ?", phone, purpose, now).
+		Order("created_at DESC").
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// AddAttempt: UPDATE ... SET attempts = attempts + 1
+// WHERE id = ? AND attempts < max RETURNING attempts
+func (postgresStore) AddAttempt(id uint, max int) (int, bool, error) {
+	var record model.OTPCode
+	res := config.DB.Model(&record).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "attempts"}}}).
+		Where("id = ? AND attempts < ?", id, max).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if res.Error != nil {
+		return 0, false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return max, false, nil
+	}
+	return record.Attempts, true, nil
+}
+
+func (postgresStore) Consume(id uint, max int, now time.Time) (bool, error) {
+	res := config.DB.Model(&model.OTPCode{}).
+		Where("id = ? AND consumed_at IS NULL AND attempts < ?", id, max).
+		Update("consumed_at", now)
+	return res.RowsAffected > 0, res.Error
+}
+
+// MemoryStore menyimpan kode di memori proses, untuk test
+type MemoryStore struct {
+	mu      sync.Mutex
+	codes   []*model.OTPCode
+	counter uint
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// newest kode terbaru yang lolos filter, dipanggil dengan mu terkunci
+func (s *MemoryStore) newest(match func(*model.OTPCode) bool) *model.OTPCode {
+	var found []*model.OTPCode
+	for _, c := range s.codes {
+		if match(c) {
+			found = append(found, c)
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].CreatedAt.After(found[j].CreatedAt) })
+	c := *found[0]
+	return &c
+}
+
+func (s *MemoryStore) Latest(phone, purpose string) (*model.OTPCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.newest(func(c *model.OTPCode) bool { return c.Phone == phone && c.Purpose == purpose }), nil
+}
+
+func (s *MemoryStore) CountSince(phone string, since time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for _, c := range s.codes {
+		if c.Phone == phone && c.CreatedAt.After(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *MemoryStore) Replace(record *model.OTPCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for _, c := range s.codes {
+		if c.Phone == record.Phone && c.Purpose == record.Purpose && c.ConsumedAt == nil {
+			c.ConsumedAt = &now
+		}
+	}
+	s.counter++
+	record.ID = s.counter
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	stored := *record
+	s.codes = append(s.codes, &stored)
+	return nil
+}
+
+func (s *MemoryStore) Active(phone, purpose string, now time.Time) (*model.OTPCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.newest(func(c *model.OTPCode) bool {
+		return c.Phone == phone && c.Purpose == purpose && c.ConsumedAt == nil && c.ExpiresAt.After(now)
+	}), nil
+}
+
+func (s *MemoryStore) AddAttempt(id uint, max int) (int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.codes {
+		if c.ID == id {
+			if c.Attempts >= max {
+				return c.Attempts, false, nil
+			}
+			c.Attempts++
+			return c.Attempts, true, nil
+		}
+	}
+	return max, false, nil
+}
+
+func (s *MemoryStore) Consume(id uint, max int, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.codes {
+		if c.ID == id && c.ConsumedAt == nil && c.Attempts < max {
+			c.ConsumedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
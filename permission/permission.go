@@ -0,0 +1,405 @@
+package permission
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/config"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Daftar permission yang dikenal aplikasi
+const (
+	OrdersCreate       = "orders.create"
+	OrdersReadOwn      = "orders.read_own"
+	OrdersReadAssigned = "orders.read_assigned"
+	OrdersReadAll      = "orders.read_all"
+	OrdersUpdate       = "orders.update"
+	OrdersUpdateStatus = "orders.update_status"
+	OrdersDelete       = "orders.delete"
+	PaymentsBill       = "payments.bill"
+	PaymentsValidate   = "payments.validate"
+	KurirTrack         = "kurir.track"
+	KurirProfile       = "kurir.profile"
+	KurirDispatch      = "kurir.dispatch"
+	KurirManage        = "kurir.manage"
+	ChatUse            = "chat.use"
+	ReportsView        = "reports.view"
+	UsersManage        = "users.manage"
+	SessionsManage     = "sessions.manage"
+	RolesManage        = "roles.manage"
+)
+
+// Catalog berisi semua permission beserta penjelasannya (untuk UI admin)
+var Catalog = map[string]string{
+	OrdersCreate:       "Membuat pesanan",
+	OrdersReadOwn:      "Melihat pesanan milik sendiri",
+	OrdersReadAssigned: "Melihat pesanan yang ditugaskan ke kurir",
+	OrdersReadAll:      "Melihat semua pesanan",
+	OrdersUpdate:       "Mengubah data pesanan mana pun",
+	OrdersUpdateStatus: "Mengubah status pesanan yang ditugaskan",
+	OrdersDelete:       "Menghapus pesanan",
+	PaymentsBill:       "Membuat tagihan pesanan",
+	PaymentsValidate:   "Memvalidasi pembayaran",
+	KurirTrack:         "Mengirim lokasi kurir",
+	KurirProfile:       "Mengubah profil kurir sendiri",
+	KurirDispatch:      "Mengatur status online kurir",
+	KurirManage:        "Mendaftarkan dan mereview kurir",
+	ChatUse:            "Memakai chat pesanan",
+	ReportsView:        "Melihat laporan & export",
+	UsersManage:        "Mengelola data user",
+	SessionsManage:     "Mengeluarkan device user",
+	RolesManage:        "Mengelola role dan permission",
+}
+
+// Role admin selalu punya semua permission dan tidak bisa diubah,
+// supaya tidak ada kemungkinan admin mengunci dirinya sendiri
+const AdminRole = "admin"
+
+// Role bawaan beserta permission default-nya
+var defaults = map[string]struct {
+	description string
+	permissions []string
+}{
+	"kurir": {"Kurir pengantar", []string{
+		OrdersReadAssigned, OrdersUpdateStatus, PaymentsBill, PaymentsValidate, KurirTrack, KurirProfile, ChatUse,
+	}},
+	"customer": {"Pelanggan", []string{
+		OrdersCreate, OrdersReadOwn, ChatUse,
+	}},
+	"finance": {"Tim keuangan", []string{
+		OrdersReadAll, PaymentsValidate, ReportsView,
+	}},
+	"dispatcher": {"Pengatur kurir & pesanan", []string{
+		OrdersReadAll, OrdersUpdate, KurirDispatch, ChatUse,
+	}},
+}
+
+var (
+	ErrUnknownPermission = errors.New("permission tidak dikenal")
+	ErrRoleNotFound      = errors.New("role tidak ditemukan")
+	ErrRoleBuiltIn       = errors.New("role bawaan tidak bisa diubah dengan cara ini")
+	ErrRoleInUse         = errors.New("role masih dipakai user")
+)
+
+// UnknownPermissionError menyebut permission yang tidak ada di Catalog;
+// errors.Is(err, ErrUnknownPermission) tetap bernilai true
+type UnknownPermissionError struct{ Name string }
+
+func (e UnknownPermissionError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnknownPermission, e.Name)
+}
+
+func (e UnknownPermissionError) Is(target error) bool { return target == ErrUnknownPermission }
+
+// Perubahan role di instance lain baru terlihat setelah cache dimuat ulang;
+// paling lambat cacheTTL setelah perubahan
+const cacheTTL = 30 * time.Second
+
+var cache = struct {
+	sync.RWMutex
+	roles    map[string]map[string]bool
+	loadedAt time.Time // nol selama masih memakai default (DB belum dimuat)
+}{}
+
+var reloading atomic.Bool
+
+func init() {
+	cache.roles = defaultRoles()
+}
+
+func defaultRoles() map[string]map[string]bool {
+	roles := map[string]map[string]bool{}
+	for name, d := range defaults {
+		roles[name] = toSet(d.permissions)
+	}
+	return roles
+}
+
+// Has mengecek apakah role punya permission
+func Has(role, perm string) bool {
+	if role == AdminRole {
+		return true
+	}
+
+	refreshIfStale()
+	cache.RLock()
+	defer cache.RUnlock()
+	return cache.roles[role][perm]
+}
+
+// RoleExists dipakai saat assign role ke user
+func RoleExists(role string) bool {
+	if role == AdminRole {
+		return true
+	}
+
+	refreshIfStale()
+	cache.RLock()
+	defer cache.RUnlock()
+	_, ok := cache.roles[role]
+	return ok
+}
+
+// Covers true kalau role punya semua permission milik target. Role admin
+// hanya bisa diberikan oleh admin, walaupun ada role lain yang isinya sama.
+func Covers(role, target string) bool {
+	if role == AdminRole {
+		return true
+	}
+	if target == AdminRole {
+		return false
+	}
+	for _, perm := range Permissions(target) {
+		if !Has(role, perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// CoversAll true kalau role punya semua perms, dipakai supaya pengelola role
+// tidak bisa membuat atau mengisi role dengan akses melebihi miliknya
+func CoversAll(role string, perms []string) bool {
+	for _, perm := range perms {
+		if !Has(role, perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// Permissions mengembalikan permission role yang sedang berlaku (terurut)
+func Permissions(role string) []string {
+	if role == AdminRole {
+		return allPermissions()
+	}
+
+	refreshIfStale()
+	cache.RLock()
+	defer cache.RUnlock()
+	return sortedKeys(cache.roles[role])
+}
+
+// Load menyiapkan role bawaan di DB (kalau belum ada) lalu memuat cache.
+// Kalau DB belum siap, permission default tetap dipakai.
+func Load() {
+	if config.DB == nil {
+		return
+	}
+
+	if err := seed(); err != nil {
+		log.Println("⚠️ Gagal menyiapkan role bawaan:", err)
+	}
+	if err := Reload(); err != nil {
+		log.Println("⚠️ Gagal memuat permission, pakai default:", err)
+	}
+}
+
+// seed hanya menambah role bawaan yang belum ada; permission yang sudah
+// diubah admin tidak ditimpa
+func seed() error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		roles := []model.Role{{Name: AdminRole, Description: "Administrator", BuiltIn: true}}
+		for name, d := range defaults {
+			roles = append(roles, model.Role{Name: name, Description: d.description, BuiltIn: true})
+		}
+
+		for _, role := range roles {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&role)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 || role.Name == AdminRole {
+				continue
+			}
+			for _, perm := range defaults[role.Name].permissions {
+				if err := tx.Create(&model.RolePermission{Role: role.Name, Permission: perm}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Reload membaca ulang role & permission dari DB ke cache
+func Reload() error {
+	var roles []model.Role
+	if err := config.DB.Find(&roles).Error; err != nil {
+		return err
+	}
+	var perms []model.RolePermission
+	if err := config.DB.Find(&perms).Error; err != nil {
+		return err
+	}
+
+	loaded := map[string]map[string]bool{}
+	for _, r := range roles {
+		if r.Name != AdminRole {
+			loaded[r.Name] = map[string]bool{}
+		}
+	}
+	for _, p := range perms {
+		if set, ok := loaded[p.Role]; ok {
+			set[p.Permission] = true
+		}
+	}
+
+	cache.Lock()
+	cache.roles = loaded
+	cache.loadedAt = time.Now()
+	cache.Unlock()
+	return nil
+}
+
+// refreshIfStale memuat ulang cache yang sudah lebih tua dari cacheTTL.
+// Hanya satu request yang memuat ulang; yang lain tetap memakai cache lama.
+func refreshIfStale() {
+	cache.RLock()
+	loadedAt := cache.loadedAt
+	cache.RUnlock()
+	if loadedAt.IsZero() || time.Since(loadedAt) < cacheTTL || config.DB == nil {
+		return
+	}
+	if !reloading.CompareAndSwap(false, true) {
+		return
+	}
+	defer reloading.Store(false)
+
+	if err := Reload(); err != nil {
+		log.Println("⚠️ Gagal memuat ulang permission, pakai cache lama:", err)
+		cache.Lock()
+		cache.loadedAt = time.Now() // coba lagi setelah cacheTTL berikutnya
+		cache.Unlock()
+	}
+}
+
+// ListRoles mengembalikan semua role beserta permission-nya
+func ListRoles() ([]model.Role, error) {
+	var roles []model.Role
+	err := config.DB.Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+// CreateRole membuat role baru (bukan bawaan) dengan permission awal
+func CreateRole(name, description string, perms []string) error {
+	if err := validatePermissions(perms); err != nil {
+		return err
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.Role{Name: name, Description: description}).Error; err != nil {
+			return err
+		}
+		return replacePermissions(tx, name, perms)
+	})
+	if err != nil {
+		return err
+	}
+	return Reload()
+}
+
+// SetPermissions mengganti seluruh permission sebuah role (kecuali admin)
+func SetPermissions(role string, perms []string) error {
+	if role == AdminRole {
+		return ErrRoleBuiltIn
+	}
+	if err := validatePermissions(perms); err != nil {
+		return err
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var r model.Role
+		if err := tx.First(&r, "name = ?", role).Error; err != nil {
+			return ErrRoleNotFound
+		}
+		return replacePermissions(tx, role, perms)
+	})
+	if err != nil {
+		return err
+	}
+	return Reload()
+}
+
+// DeleteRole menghapus role buatan admin yang sudah tidak dipakai user
+func DeleteRole(role string) error {
+	var r model.Role
+	if err := config.DB.First(&r, "name = ?", role).Error; err != nil {
+		return ErrRoleNotFound
+	}
+	if r.BuiltIn {
+		return ErrRoleBuiltIn
+	}
+
+	var users int64
+	config.DB.Model(&model.User{}).Where("role = ?", role).Count(&users)
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&r).Error
+	})
+	if err != nil {
+		return err
+	}
+	return Reload()
+}
+
+func replacePermissions(tx *gorm.DB, role string, perms []string) error {
+	if err := tx.Where("role = ?", role).Delete(&model.RolePermission{}).Error; err != nil {
+		return err
+	}
+	for perm := range toSet(perms) {
+		if err := tx.Create(&model.RolePermission{Role: role, Permission: perm}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePermissions(perms []string) error {
+	for _, p := range perms {
+		if _, ok := Catalog[p]; !ok {
+			return UnknownPermissionError{Name: p}
+		}
+	}
+	return nil
+}
+
+func allPermissions() []string {
+	out := make([]string, 0, len(Catalog))
+	for p := range Catalog {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func toSet(perms []string) map[string]bool {
+	set := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		set[p] = true
+	}
+	return set
+}
+
+func sortedKeys(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for p := range set {
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out
+}
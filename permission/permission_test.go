@@ -0,0 +1,46 @@
+package permission
+
+import "testing"
+
+func TestCovers(t *testing.T) {
+	cases := []struct {
+		role, target string
+		want         bool
+	}{
+		{"admin", "admin", true},
+		{"admin", "customer", true},
+		{"dispatcher", "admin", false},
+		{"dispatcher", "customer", false}, // customer punya orders.create
+		{"dispatcher", "finance", false},  // finance punya reports.view
+		{"kurir", "kurir", true},
+		{"customer", "kurir", false},
+		{"finance", "dispatcher", false},
+	}
+	for _, tc := range cases {
+		if got := Covers(tc.role, tc.target); got != tc.want {
+			t.Errorf("Covers(%s, %s) = %v, want %v", tc.role, tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestCoversAll(t *testing.T) {
+	cases := []struct {
+		name  string
+		role  string
+		perms []string
+		want  bool
+	}{
+		{"admin grants anything", "admin", []string{RolesManage, UsersManage}, true},
+		{"own permissions", "dispatcher", []string{OrdersReadAll, KurirDispatch}, true},
+		{"empty set", "dispatcher", nil, true},
+		{"roles.manage not held", "dispatcher", []string{OrdersReadAll, RolesManage}, false},
+		{"users.manage not held", "finance", []string{UsersManage}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CoversAll(tc.role, tc.perms); got != tc.want {
+				t.Fatalf("CoversAll(%s, %v) = %v, want %v", tc.role, tc.perms, got, tc.want)
+			}
+		})
+	}
+}
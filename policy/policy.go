@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
+)
+
+// Actor adalah user yang sedang melakukan request (diambil dari token)
+type Actor struct {
+	ID   uint
+	Role string
+}
+
+// ActorFrom actor request ini, diisi middleware.JWTAuthMiddleware
+func ActorFrom(c *gin.Context) Actor {
+	return Actor{ID: c.GetUint("userID"), Role: c.GetString("role")}
+}
+
+// Can mengecek permission role actor
+func (a Actor) Can(perm string) bool {
+	return permission.Has(a.Role, perm)
+}
+
+func (a Actor) owns(order model.Order) bool {
+	return a.ID != 0 && order.CustomerID == a.ID
+}
+
+func (a Actor) assignedTo(order model.Order) bool {
+	return a.ID != 0 && order.KurirID == a.ID
+}
+
+// ViewOrder: pengelola melihat semua, customer pesanannya sendiri,
+// kurir pesanan yang ditugaskan ke dia
+func ViewOrder(a Actor, order model.Order) bool {
+	switch {
+	case a.Can(permission.OrdersReadAll):
+		return true
+	case a.Can(permission.OrdersReadOwn) && a.owns(order):
+		return true
+	case a.Can(permission.OrdersReadAssigned) && a.assignedTo(order):
+		return true
+	}
+	return false
+}
+
+// EditOrder: mengubah seluruh field pesanan, hanya untuk pengelola
+func EditOrder(a Actor, order model.Order) bool {
+	return a.Can(permission.OrdersUpdate)
+}
+
+// UpdateOrderStatus: pengelola, atau kurir yang ditugaskan
+func UpdateOrderStatus(a Actor, order model.Order) bool {
+	return EditOrder(a, order) || (a.Can(permission.OrdersUpdateStatus) && a.assignedTo(order))
+}
+
+// BillOrder: membuat tagihan, oleh kurir yang mengantar atau pengelola
+func BillOrder(a Actor, order model.Order) bool {
+	return a.Can(permission.PaymentsBill) && (a.assignedTo(order) || a.Can(permission.OrdersUpdate))
+}
+
+// ValidatePayment: kurir yang mengantar (bayar tunai) atau tim yang bisa
+// melihat semua pesanan, mis. finance
+func ValidatePayment(a Actor, order model.Order) bool {
+	return a.Can(permission.PaymentsValidate) && (a.assignedTo(order) || a.Can(permission.OrdersReadAll))
+}
+
+// ChoosePaymentMethod: customer pemilik, kurir yang mengantar, atau pengelola
+func ChoosePaymentMethod(a Actor, order model.Order) bool {
+	return a.owns(order) || a.assignedTo(order) || EditOrder(a, order)
+}
+
+// ViewKurirOrders: data pesanan & pendapatan seorang kurir hanya untuk
+// kurir itu sendiri atau yang bisa melihat semua pesanan
+func ViewKurirOrders(a Actor, kurirID uint) bool {
+	return (a.ID != 0 && a.ID == kurirID) || a.Can(permission.OrdersReadAll)
+}
+
+// EditKurirProfile: kurir hanya boleh mengubah profilnya sendiri
+func EditKurirProfile(a Actor, kurirID uint) bool {
+	if a.Can(permission.KurirManage) {
+		return true
+	}
+	return a.Can(permission.KurirProfile) && a.ID != 0 && a.ID == kurirID
+}
+
+// Chat: hanya customer & kurir pesanan itu, atau pengelola pesanan
+func Chat(a Actor, order model.Order) bool {
+	return a.Can(permission.ChatUse) && (a.owns(order) || a.assignedTo(order) || a.Can(permission.OrdersUpdate))
+}
+
+// ClearChat: menghapus seluruh chat pesanan
+func ClearChat(a Actor, order model.Order) bool {
+	return Chat(a, order) || a.Can(permission.OrdersDelete)
+}
+
+// ViewKurirContact: nomor HP kurir hanya untuk yang mengatur pengiriman
+func ViewKurirContact(a Actor) bool {
+	return a.Can(permission.KurirDispatch) || a.Can(permission.OrdersReadAll)
+}
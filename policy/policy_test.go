@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// Aktor dengan role bawaan. Pesanan uji milik customerA dan diantar kurir.
+var (
+	admin      = Actor{ID: 100, Role: "admin"}
+	dispatcher = Actor{ID: 101, Role: "dispatcher"}
+	finance    = Actor{ID: 102, Role: "finance"}
+	customerA  = Actor{ID: 1, Role: "customer"}
+	customerB  = Actor{ID: 2, Role: "customer"}
+	kurir      = Actor{ID: 10, Role: "kurir"}
+	kurirLain  = Actor{ID: 11, Role: "kurir"}
+	anonymous  = Actor{}
+)
+
+var actors = []struct {
+	name  string
+	actor Actor
+}{
+	{"admin", admin},
+	{"dispatcher", dispatcher},
+	{"finance", finance},
+	{"customerA", customerA},
+	{"customerB", customerB},
+	{"kurir", kurir},
+	{"kurirLain", kurirLain},
+	{"anonymous", anonymous},
+}
+
+var order = model.Order{ID: 1, CustomerID: customerA.ID, KurirID: kurir.ID}
+
+func TestOrderPolicies(t *testing.T) {
+	cases := []struct {
+		name    string
+		allow   func(Actor, model.Order) bool
+		allowed []Actor
+	}{
+		{"ViewOrder", ViewOrder, []Actor{admin, dispatcher, finance, customerA, kurir}},
+		{"EditOrder", EditOrder, []Actor{admin, dispatcher}},
+		{"UpdateOrderStatus", UpdateOrderStatus, []Actor{admin, dispatcher, kurir}},
+		{"BillOrder", BillOrder, []Actor{admin, kurir}},
+		{"ValidatePayment", ValidatePayment, []Actor{admin, finance, kurir}},
+		{"ChoosePaymentMethod", ChoosePaymentMethod, []Actor{admin, dispatcher, customerA, kurir}},
+		{"Chat", Chat, []Actor{admin, dispatcher, customerA, kurir}},
+		{"ClearChat", ClearChat, []Actor{admin, dispatcher, customerA, kurir}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, a := range actors {
+				want := contains(tc.allowed, a.actor)
+				if got := tc.allow(a.actor, order); got != want {
+					t.Errorf("%s: got %v, want %v", a.name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestKurirPolicies(t *testing.T) {
+	cases := []struct {
+		name    string
+		allow   func(Actor, uint) bool
+		allowed []Actor
+	}{
+		{"ViewKurirOrders", ViewKurirOrders, []Actor{admin, dispatcher, finance, kurir}},
+		{"EditKurirProfile", EditKurirProfile, []Actor{admin, kurir}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, a := range actors {
+				want := contains(tc.allowed, a.actor)
+				if got := tc.allow(a.actor, kurir.ID); got != want {
+					t.Errorf("%s: got %v, want %v", a.name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestViewKurirContact(t *testing.T) {
+	allowed := []Actor{admin, dispatcher, finance}
+	for _, a := range actors {
+		want := contains(allowed, a.actor)
+		if got := ViewKurirContact(a.actor); got != want {
+			t.Errorf("%s: got %v, want %v", a.name, got, want)
+		}
+	}
+}
+
+func contains(list []Actor, a Actor) bool {
+	for _, item := range list {
+		if item == a {
+			return true
+		}
+	}
+	return false
+}
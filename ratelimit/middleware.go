@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+)
+
+// KeyFunc menentukan siapa yang dibatasi
+type KeyFunc func(c *gin.Context) string
+
+// ByIP untuk route publik (login, register, OTP)
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUser memakai userID dari JWT, jatuh ke IP kalau belum login
+func ByUser(c *gin.Context) string {
+	if id := c.GetUint("userID"); id != 0 {
+		return fmt.Sprintf("user:%d", id)
+	}
+	return ByIP(c)
+}
+
+// Middleware membatasi request sesuai policy. Kalau store error, request
+// tetap diloloskan supaya gangguan DB tidak mematikan seluruh API.
+func Middleware(p Policy, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := Take(c.Request.Context(), p, key(c))
+		if err != nil {
+			middleware.Logf(c, "⚠️ Rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", p.header())
+		c.Header("RateLimit-Limit", strconv.Itoa(int(p.capacity())))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+
+		if !res.Allowed {
+			c.Header("Retry-After", RetryAfterHeader(res))
+			c.Error(apperror.TooManyRequests)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Take mengambil satu token dari bucket p untuk key. Dipakai langsung oleh
+// handler kalau kuncinya baru diketahui dari body, mis. nomor HP.
+func Take(ctx context.Context, p Policy, key string) (Result, error) {
+	return store.Take(ctx, p.Name+":"+key, p, time.Now())
+}
+
+// RetryAfterHeader nilai header Retry-After untuk hasil yang ditolak
+func RetryAfterHeader(res Result) string {
+	return ceilSeconds(res.RetryAfter)
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
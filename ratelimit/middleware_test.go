@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+)
+
+// newLimitedRouter merakit router seperti main.go: proxy yang dipercaya
+// diatur lewat SetTrustedProxies sebelum rate limit dipasang
+func newLimitedRouter(t *testing.T, trusted []string, p Policy) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	prev := store
+	SetStore(NewMemoryStore())
+	t.Cleanup(func() { SetStore(prev) })
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(trusted); err != nil {
+		t.Fatal(err)
+	}
+	r.Use(middleware.ErrorHandler())
+	r.GET("/login", Middleware(p, ByIP), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func get(r *gin.Engine, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/login", nil) // RemoteAddr 192.0.2.1
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestSpoofedForwardedForDoesNotResetBucket(t *testing.T) {
+	p := Policy{Name: "auth", Limit: 2, Period: time.Minute, Burst: 2}
+	r := newLimitedRouter(t, nil, p)
+
+	spoofed := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"}
+	for i, ip := range spoofed {
+		want := http.StatusOK
+		if i >= p.Burst {
+			want = http.StatusTooManyRequests
+		}
+		if got := get(r, ip); got != want {
+			t.Fatalf("request %d (X-Forwarded-For %s): status %d, want %d", i+1, ip, got, want)
+		}
+	}
+}
+
+// Di belakang proxy yang dipercaya, setiap klien asli tetap dapat bucket sendiri
+func TestTrustedProxyForwardedForSeparatesClients(t *testing.T) {
+	p := Policy{Name: "auth", Limit: 1, Period: time.Minute, Burst: 1}
+	r := newLimitedRouter(t, []string{"192.0.2.1"}, p)
+
+	if got := get(r, "198.51.100.7"); got != http.StatusOK {
+		t.Fatalf("first client: status %d", got)
+	}
+	if got := get(r, "198.51.100.7"); got != http.StatusTooManyRequests {
+		t.Fatalf("first client again: status %d, want 429", got)
+	}
+	if got := get(r, "198.51.100.8"); got != http.StatusOK {
+		t.Fatalf("second client: status %d", got)
+	}
+}
+
+// failingStore meniru store yang tidak bisa dihubungi
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Policy, time.Time) (Result, error) {
+	return Result{}, errors.New("connection refused")
+}
+
+// Gangguan store tidak boleh mematikan API: request tetap diloloskan
+func TestMiddlewareFailsOpen(t *testing.T) {
+	r := newLimitedRouter(t, nil, Policy{Name: "auth", Limit: 1, Period: time.Minute, Burst: 1})
+	SetStore(failingStore{})
+
+	for i := 1; i <= 3; i++ {
+		if got := get(r, ""); got != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, got)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Baris bucket yang tidak tersentuh selama ini dihapus berkala
+const postgresIdleTTL = 24 * time.Hour
+
+// PostgresStore menyimpan bucket di tabel rate_limit_buckets sehingga
+// batasnya berlaku bersama untuk semua instance server
+type PostgresStore struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error) {
+	var res Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row model.RateLimitBucket
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, "key = ?", key).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		b := bucket{tokens: row.Tokens, updated: row.UpdatedAt}
+		res = b.take(p, now)
+
+		row = model.RateLimitBucket{Key: key, Tokens: b.tokens, UpdatedAt: b.updated}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"tokens", "updated_at"}),
+		}).Create(&row).Error
+	})
+
+	s.maybePrune(now)
+	return res, err
+}
+
+func (s *PostgresStore) maybePrune(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < time.Hour {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	go func() {
+		if err := s.db.Where("updated_at < ?", now.Add(-postgresIdleTTL)).Delete(&model.RateLimitBucket{}).Error; err != nil {
+			log.Println("⚠️ Gagal membersihkan rate_limit_buckets:", err)
+		}
+	}()
+}
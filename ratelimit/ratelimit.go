@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Policy adalah aturan token bucket: Limit token diisi ulang setiap Period,
+// dengan kapasitas Burst (lonjakan yang masih diizinkan)
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 {
+		return float64(p.Burst)
+	}
+	return float64(p.Limit)
+}
+
+// refillRate dalam token per detik
+func (p Policy) refillRate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// header RateLimit-Policy, mis. "30;w=60;burst=10"
+func (p Policy) header() string {
+	return fmt.Sprintf("%d;w=%d;burst=%d", p.Limit, int(p.Period.Seconds()), int(p.capacity()))
+}
+
+// Result adalah hasil satu pengambilan token
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // kapan token berikutnya tersedia (kalau ditolak)
+	Reset      time.Duration // kapan bucket penuh kembali
+}
+
+// Store menyimpan state bucket. Implementasi lain (mis. Redis) cukup
+// memenuhi interface ini lalu dipasang lewat SetStore.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
+
+var store Store = NewMemoryStore()
+
+// SetStore mengganti store default (MemoryStore)
+func SetStore(s Store) {
+	store = s
+}
+
+// bucket adalah state token bucket yang sama untuk semua store
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take mengisi ulang bucket sesuai waktu yang lewat lalu mengambil satu token
+func (b *bucket) take(p Policy, now time.Time) Result {
+	capacity, rate := p.capacity(), p.refillRate()
+
+	if b.updated.IsZero() {
+		b.tokens = capacity
+	} else if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	}
+	b.updated = now
+
+	res := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((capacity - b.tokens) / rate)
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTokenBucket(t *testing.T) {
+	// 1 token per detik, kapasitas 3
+	perSecond := Policy{Name: "test", Limit: 60, Period: time.Minute, Burst: 3}
+
+	type step struct {
+		at        time.Duration // sejak awal test
+		key       string
+		allowed   bool
+		remaining int
+	}
+	cases := []struct {
+		name  string
+		p     Policy
+		steps []step
+	}{
+		{"burst then deny", perSecond, []step{
+			{0, "a", true, 2},
+			{0, "a", true, 1},
+			{0, "a", true, 0},
+			{0, "a", false, 0},
+		}},
+		{"refill one token per second", perSecond, []step{
+			{0, "a", true, 2},
+			{0, "a", true, 1},
+			{0, "a", true, 0},
+			{500 * time.Millisecond, "a", false, 0},
+			{time.Second, "a", true, 0},
+			{time.Second, "a", false, 0},
+			{3 * time.Second, "a", true, 1},
+		}},
+		{"refill capped at burst", perSecond, []step{
+			{0, "a", true, 2},
+			{time.Hour, "a", true, 2},
+			{time.Hour, "a", true, 1},
+			{time.Hour, "a", true, 0},
+			{time.Hour, "a", false, 0},
+		}},
+		{"keys are isolated", perSecond, []step{
+			{0, "a", true, 2},
+			{0, "a", true, 1},
+			{0, "a", true, 0},
+			{0, "a", false, 0},
+			{0, "b", true, 2},
+			{0, "a", false, 0},
+		}},
+		{"no burst means limit", Policy{Name: "test", Limit: 2, Period: time.Minute}, []step{
+			{0, "a", true, 1},
+			{0, "a", true, 0},
+			{0, "a", false, 0},
+			{30 * time.Second, "a", true, 0},
+		}},
+	}
+
+	start := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewMemoryStore()
+			for i, st := range tc.steps {
+				res, err := s.Take(context.Background(), st.key, tc.p, start.Add(st.at))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if res.Allowed != st.allowed || res.Remaining != st.remaining {
+					t.Fatalf("step %d (%s at %v): allowed %v remaining %d, want %v %d",
+						i+1, st.key, st.at, res.Allowed, res.Remaining, st.allowed, st.remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreRetryAfter(t *testing.T) {
+	p := Policy{Name: "test", Limit: 6, Period: time.Minute, Burst: 1} // 1 token per 10 detik
+	s := NewMemoryStore()
+	now := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	s.Take(context.Background(), "a", p, now)
+	res, _ := s.Take(context.Background(), "a", p, now.Add(4*time.Second))
+	if res.Allowed {
+		t.Fatal("allowed with an empty bucket")
+	}
+	if d := res.RetryAfter - 6*time.Second; d < -time.Millisecond || d > time.Millisecond {
+		t.Fatalf("retry after %v, want 6s", res.RetryAfter)
+	}
+	if got := RetryAfterHeader(res); got != "6" {
+		t.Fatalf("Retry-After %q, want \"6\"", got)
+	}
+}
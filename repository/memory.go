@@ -0,0 +1,414 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+)
+
+// MemoryUsers menyimpan user di memori. Dipakai sebagai fake di test.
+type MemoryUsers struct {
+	mu     sync.RWMutex
+	nextID uint
+	users  map[uint]model.User
+}
+
+func NewMemoryUsers() *MemoryUsers {
+	return &MemoryUsers{users: map[uint]model.User{}}
+}
+
+func (r *MemoryUsers) FindByID(_ context.Context, id uint) (model.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return model.User{}, ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *MemoryUsers) FindKurir(ctx context.Context, id uint) (model.User, error) {
+	user, err := r.FindByID(ctx, id)
+	if err == nil && user.Role != "kurir" {
+		return model.User{}, ErrNotFound
+	}
+	return user, err
+}
+
+func (r *MemoryUsers) FindByLogin(_ context.Context, email, phone string) (model.User, error) {
+	users := r.filter(func(u model.User) bool {
+		return (email != "" && strings.EqualFold(u.Email, email)) || (phone != "" && u.Phone == phone)
+	})
+	if len(users) == 0 {
+		return model.User{}, ErrNotFound
+	}
+	return users[0], nil
+}
+
+func (r *MemoryUsers) List(_ context.Context) ([]model.User, error) {
+	return r.filter(func(model.User) bool { return true }), nil
+}
+
+func (r *MemoryUsers) AvailableKurir(_ context.Context) ([]model.User, error) {
+	return r.filter(func(u model.User) bool {
+		return u.Role == "kurir" && u.Status == "online" && u.StatusKerja == "aktif"
+	}), nil
+}
+
+func (r *MemoryUsers) ContactTaken(_ context.Context, email, phone string, exceptID uint) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.ID == exceptID {
+			continue
+		}
+		if email != "" && strings.EqualFold(u.Email, email) {
+			return "email", nil
+		}
+	}
+	for _, u := range r.users {
+		if u.ID != exceptID && phone != "" && u.Phone == phone {
+			return "phone", nil
+		}
+	}
+	return "", nil
+}
+
+// Save membuat user baru kalau ID masih 0
+func (r *MemoryUsers) Save(_ context.Context, user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if user.ID == 0 {
+		r.nextID++
+		user.ID = r.nextID
+	} else if user.ID > r.nextID {
+		r.nextID = user.ID
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *MemoryUsers) SetKurirStatus(_ context.Context, id uint, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if u, ok := r.users[id]; ok && u.Role == "kurir" {
+		u.Status = status
+		r.users[id] = u
+	}
+	return nil
+}
+
+// filter mengembalikan user urut ID
+func (r *MemoryUsers) filter(keep func(model.User) bool) []model.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []model.User
+	for _, u := range r.users {
+		if keep(u) {
+			out = append(out, u)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// lookup dipakai repository lain untuk mengisi relasi user
+func (r *MemoryUsers) lookup(id uint) model.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.users[id]
+}
+
+// MemoryOrders menyimpan pesanan di memori; Customer dan Kurir diisi dari
+// MemoryUsers seperti Preload
+type MemoryOrders struct {
+	mu     sync.RWMutex
+	nextID uint
+	orders map[uint]model.Order
+	users  *MemoryUsers
+}
+
+func NewMemoryOrders(users *MemoryUsers) *MemoryOrders {
+	return &MemoryOrders{orders: map[uint]model.Order{}, users: users}
+}
+
+func (r *MemoryOrders) Create(_ context.Context, order *model.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	now := time.Now()
+	order.ID = r.nextID
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	r.orders[order.ID] = *order
+	return nil
+}
+
+func (r *MemoryOrders) FindByID(_ context.Context, id uint) (model.Order, error) {
+	r.mu.RLock()
+	order, ok := r.orders[id]
+	r.mu.RUnlock()
+
+	if !ok {
+		return model.Order{}, ErrNotFound
+	}
+	return r.withUsers(order), nil
+}
+
+func (r *MemoryOrders) Save(_ context.Context, order *model.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.orders[order.ID]; !ok {
+		return ErrNotFound
+	}
+	order.UpdatedAt = time.Now()
+	stored := *order
+	stored.Customer, stored.Kurir = model.User{}, model.User{}
+	r.orders[order.ID] = stored
+	return nil
+}
+
+func (r *MemoryOrders) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.orders, id)
+	return nil
+}
+
+func (r *MemoryOrders) List(_ context.Context, q OrderListQuery) ([]model.Order, int64, error) {
+	var orders []model.Order
+	for _, o := range r.filter(func(model.Order) bool { return true }) {
+		if q.Filter.match(o) {
+			orders = append(orders, o)
+		}
+	}
+
+	less := orderLess[q.Sort]
+	if less == nil {
+		less = orderLess["id"]
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		a, b := orders[i], orders[j]
+		if q.Desc {
+			a, b = b, a
+		}
+		if less(a, b) || less(b, a) {
+			return less(a, b)
+		}
+		return a.ID < b.ID
+	})
+
+	total := int64(len(orders))
+	start := (q.Page - 1) * q.Limit
+	if start > len(orders) {
+		start = len(orders)
+	}
+	end := start + q.Limit
+	if end > len(orders) {
+		end = len(orders)
+	}
+	return orders[start:end], total, nil
+}
+
+// orderLess urutan naik untuk setiap key OrderSortColumns
+var orderLess = map[string]func(a, b model.Order) bool{
+	"id":             func(a, b model.Order) bool { return a.ID < b.ID },
+	"created_at":     func(a, b model.Order) bool { return a.CreatedAt.Before(b.CreatedAt) },
+	"updated_at":     func(a, b model.Order) bool { return a.UpdatedAt.Before(b.UpdatedAt) },
+	"status":         func(a, b model.Order) bool { return a.Status < b.Status },
+	"nominal":        func(a, b model.Order) bool { return derefOr(a.Nominal, 0) < derefOr(b.Nominal, 0) },
+	"payment_status": func(a, b model.Order) bool { return derefOr(a.PaymentStatus, "") < derefOr(b.PaymentStatus, "") },
+}
+
+func derefOr[T any](p *T, fallback T) T {
+	if p == nil {
+		return fallback
+	}
+	return *p
+}
+
+func (r *MemoryOrders) UpdateStatus(_ context.Context, id uint, status string) error {
+	return r.update(id, func(o *model.Order) { o.Status = status })
+}
+
+func (r *MemoryOrders) UpdatePaymentStatus(_ context.Context, id uint, status string) error {
+	return r.update(id, func(o *model.Order) { o.PaymentStatus = &status })
+}
+
+func (r *MemoryOrders) ListByCustomer(_ context.Context, customerID uint) ([]model.Order, error) {
+	return r.filter(func(o model.Order) bool { return o.CustomerID == customerID }), nil
+}
+
+func (r *MemoryOrders) ListByKurir(_ context.Context, q KurirOrderQuery) ([]model.Order, error) {
+	return r.filter(func(o model.Order) bool {
+		return o.KurirID == q.KurirID &&
+			(q.Status == "" || o.Status == q.Status) &&
+			(q.From == nil || !o.UpdatedAt.Before(*q.From)) &&
+			(q.To == nil || o.UpdatedAt.Before(*q.To))
+	}), nil
+}
+
+func (r *MemoryOrders) CountActiveByKurir(_ context.Context, kurirID uint) (int64, error) {
+	active := r.filter(func(o model.Order) bool { return o.KurirID == kurirID && o.Status == "proses" })
+	return int64(len(active)), nil
+}
+
+func (r *MemoryOrders) HasActiveDelivery(_ context.Context, customerID, kurirID uint) (bool, error) {
+	active := r.filter(func(o model.Order) bool {
+		return o.CustomerID == customerID && o.KurirID == kurirID && o.Status == "proses"
+	})
+	return len(active) > 0, nil
+}
+
+func (r *MemoryOrders) Revenue(_ context.Context, q RevenueQuery) (float64, error) {
+	var total float64
+	for _, o := range r.completed(q.From, q.To) {
+		if (q.KurirID == 0 || o.KurirID == q.KurirID) && o.Nominal != nil {
+			total += float64(*o.Nominal)
+		}
+	}
+	return total, nil
+}
+
+func (r *MemoryOrders) CountCompleted(_ context.Context, from, to time.Time) (int64, error) {
+	return int64(len(r.completed(from, to))), nil
+}
+
+func (r *MemoryOrders) completed(from, to time.Time) []model.Order {
+	return r.filter(func(o model.Order) bool {
+		return o.Status == "selesai" && !o.UpdatedAt.Before(from) && o.UpdatedAt.Before(to)
+	})
+}
+
+func (r *MemoryOrders) update(id uint, change func(*model.Order)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return nil // sama seperti UPDATE tanpa baris yang cocok
+	}
+	change(&order)
+	order.UpdatedAt = time.Now()
+	r.orders[id] = order
+	return nil
+}
+
+// filter mengembalikan pesanan urut ID dengan relasi user terisi
+func (r *MemoryOrders) filter(keep func(model.Order) bool) []model.Order {
+	r.mu.RLock()
+	var out []model.Order
+	for _, o := range r.orders {
+		if keep(o) {
+			out = append(out, o)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	for i := range out {
+		out[i] = r.withUsers(out[i])
+	}
+	return out
+}
+
+func (r *MemoryOrders) withUsers(o model.Order) model.Order {
+	o.Customer = r.users.lookup(o.CustomerID)
+	o.Kurir = r.users.lookup(o.KurirID)
+	return o
+}
+
+// MemoryMessages menyimpan chat di memori
+type MemoryMessages struct {
+	mu       sync.RWMutex
+	nextID   uint
+	messages []model.Message
+	users    *MemoryUsers
+}
+
+func NewMemoryMessages(users *MemoryUsers) *MemoryMessages {
+	return &MemoryMessages{users: users}
+}
+
+func (r *MemoryMessages) Create(_ context.Context, msg *model.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	msg.ID = r.nextID
+	stored := *msg
+	stored.Sender = model.User{}
+	r.messages = append(r.messages, stored)
+	return nil
+}
+
+func (r *MemoryMessages) ListByOrder(_ context.Context, orderID uint) ([]model.Message, error) {
+	r.mu.RLock()
+	var out []model.Message
+	for _, m := range r.messages {
+		if m.OrderID == orderID {
+			out = append(out, m)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SentAt.Before(out[j].SentAt) })
+	for i := range out {
+		out[i].Sender = r.users.lookup(out[i].SenderID)
+	}
+	return out, nil
+}
+
+func (r *MemoryMessages) DeleteByOrder(_ context.Context, orderID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.messages[:0]
+	for _, m := range r.messages {
+		if m.OrderID != orderID {
+			kept = append(kept, m)
+		}
+	}
+	r.messages = kept
+	return nil
+}
+
+// MemoryLocations menyimpan lokasi terakhir kurir di memori proses.
+// Ini juga implementasi yang dipakai di production.
+type MemoryLocations struct {
+	mu   sync.RWMutex
+	data map[uint]Location
+}
+
+func NewMemoryLocations() *MemoryLocations {
+	return &MemoryLocations{data: map[uint]Location{}}
+}
+
+func (r *MemoryLocations) Set(_ context.Context, kurirID uint, loc Location) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data[kurirID] = loc
+	return nil
+}
+
+func (r *MemoryLocations) Get(_ context.Context, kurirID uint) (Location, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	loc, ok := r.data[kurirID]
+	if !ok {
+		return Location{}, ErrNotFound
+	}
+	return loc, nil
+}
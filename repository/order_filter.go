@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+)
+
+// OrderFilter filter daftar dan export pesanan
+type OrderFilter struct {
+	Status        string
+	PaymentStatus string
+	Layanan       string
+	KurirID       uint
+	CustomerID    uint
+	From          *time.Time
+	To            *time.Time // eksklusif
+	Search        string
+}
+
+// OrderListQuery satu halaman daftar pesanan. Sort adalah key di
+// OrderSortColumns; id selalu jadi tie-breaker.
+type OrderListQuery struct {
+	Filter OrderFilter
+	Sort   string
+	Desc   bool
+	Page   int
+	Limit  int
+}
+
+// Kolom yang boleh dipakai untuk ?sort=
+var OrderSortColumns = map[string]string{
+	"id":             "orders.id",
+	"created_at":     "orders.created_at",
+	"updated_at":     "orders.updated_at",
+	"nominal":        "orders.nominal",
+	"status":         "orders.status",
+	"payment_status": "orders.payment_status",
+}
+
+// JoinOrderUsers menambahkan alias customer & kurir, dibutuhkan untuk pencarian nama
+func JoinOrderUsers(q *gorm.DB) *gorm.DB {
+	return q.
+		Joins("LEFT JOIN public.users AS customer ON customer.id = orders.customer_id").
+		Joins("LEFT JOIN public.users AS kurir ON kurir.id = orders.kurir_id")
+}
+
+// Apply mengharuskan query sudah melalui JoinOrderUsers
+func (f OrderFilter) Apply(q *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		q = q.Where("orders.status = ?", f.Status)
+	}
+	if f.PaymentStatus != "" {
+		q = q.Where("orders.payment_status = ?", f.PaymentStatus)
+	}
+	if f.Layanan != "" {
+		q = q.Where("orders.layanan = ?", f.Layanan)
+	}
+	if f.KurirID != 0 {
+		q = q.Where("orders.kurir_id = ?", f.KurirID)
+	}
+	if f.CustomerID != 0 {
+		q = q.Where("orders.customer_id = ?", f.CustomerID)
+	}
+	if f.From != nil {
+		q = q.Where("orders.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("orders.created_at < ?", *f.To)
+	}
+	if f.Search != "" {
+		like := containsPattern(f.Search)
+		q = q.Where(
+			`CAST(orders.id AS TEXT) = ? OR customer.name ILIKE ? ESCAPE '\' `+
+				`OR kurir.name ILIKE ? ESCAPE '\' OR orders.layanan ILIKE ? ESCAPE '\'`,
+			f.Search, like, like, like,
+		)
+	}
+	return q
+}
+
+// likeEscaper supaya %, _ dan \ dari input dicari apa adanya, bukan wildcard
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// containsPattern pola ILIKE "mengandung s"
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+// match versi memori dari Apply; Customer dan Kurir harus sudah terisi
+func (f OrderFilter) match(o model.Order) bool {
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(f.Search))
+	}
+	return (f.Status == "" || o.Status == f.Status) &&
+		(f.PaymentStatus == "" || (o.PaymentStatus != nil && *o.PaymentStatus == f.PaymentStatus)) &&
+		(f.Layanan == "" || o.Layanan == f.Layanan) &&
+		(f.KurirID == 0 || o.KurirID == f.KurirID) &&
+		(f.CustomerID == 0 || o.CustomerID == f.CustomerID) &&
+		(f.From == nil || !o.CreatedAt.Before(*f.From)) &&
+		(f.To == nil || o.CreatedAt.Before(*f.To)) &&
+		(f.Search == "" || strconv.FormatUint(uint64(o.ID), 10) == f.Search ||
+			contains(o.Customer.Name) || contains(o.Kurir.Name) || contains(o.Layanan))
+}
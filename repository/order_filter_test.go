@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun menyusun SQL tanpa koneksi ke database
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestContainsPattern(t *testing.T) {
+	cases := map[string]string{
+		"budi":      "%budi%",
+		"50%":       `%50\%%`,
+		"a_b":       `%a\_b%`,
+		`C:\temp`:   `%C:\\temp%`,
+		`\%`:        `%\\\%%`,
+		"100% cash": `%100\% cash%`,
+	}
+	for in, want := range cases {
+		if got := containsPattern(in); got != want {
+			t.Errorf("containsPattern(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOrderFilterApplySQL(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	f := OrderFilter{Status: "selesai", KurirID: 7, From: &from, To: &to, Search: "50%_off"}
+
+	var orders []model.Order
+	stmt := f.Apply(JoinOrderUsers(dryRun(t).Model(&model.Order{}))).Find(&orders).Statement
+	sql := stmt.SQL.String()
+
+	for _, want := range []string{
+		"orders.status = $1", "orders.kurir_id = $2",
+		"orders.created_at >= $3", "orders.created_at < $4",
+		`customer.name ILIKE $6 ESCAPE '\'`, `kurir.name ILIKE $7 ESCAPE '\'`, `orders.layanan ILIKE $8 ESCAPE '\'`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("SQL does not contain %q:\n%s", want, sql)
+		}
+	}
+	if got := stmt.Vars[4]; got != "50%_off" {
+		t.Errorf("id search var %v, want the raw term", got)
+	}
+	for _, v := range stmt.Vars[5:8] {
+		if v != `%50\%\_off%` {
+			t.Errorf("like var %v, want escaped wildcards", v)
+		}
+	}
+}
+
+// Versi memori juga mencari % dan _ apa adanya
+func TestOrderFilterMatchSearch(t *testing.T) {
+	order := model.Order{
+		ID: 12, Layanan: "antar barang",
+		Customer: model.User{Name: "Budi"}, Kurir: model.User{Name: "Siti_Kurir"},
+	}
+	cases := map[string]bool{
+		"budi":  true,
+		"SITI_": true,
+		"12":    true,
+		"1":     false,
+		"%":     false,
+		"b_di":  false,
+		"antar": true,
+	}
+	for search, want := range cases {
+		if got := (OrderFilter{Search: search}).match(order); got != want {
+			t.Errorf("search %q: match %v, want %v", search, got, want)
+		}
+	}
+}
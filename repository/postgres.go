@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// PostgresUsers menyimpan user di tabel users
+type PostgresUsers struct {
+	db *gorm.DB
+}
+
+func NewPostgresUsers(db *gorm.DB) *PostgresUsers {
+	return &PostgresUsers{db: db}
+}
+
+func (r *PostgresUsers) FindByID(ctx context.Context, id uint) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).First(&user, id).Error
+	return user, notFound(err)
+}
+
+func (r *PostgresUsers) FindKurir(ctx context.Context, id uint) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).First(&user, "id = ? AND role = ?", id, "kurir").Error
+	return user, notFound(err)
+}
+
+func (r *PostgresUsers) FindByLogin(ctx context.Context, email, phone string) (model.User, error) {
+	var user model.User
+	err := r.db.WithContext(ctx).
+		Where("LOWER(email) = LOWER(?) OR phone = ?", email, phone).
+		First(&user).Error
+	return user, notFound(err)
+}
+
+func (r *PostgresUsers) List(ctx context.Context) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Find(&users).Error
+	return users, err
+}
+
+func (r *PostgresUsers) AvailableKurir(ctx context.Context) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("role = ? AND status = ? AND status_kerja = ?", "kurir", "online", "aktif").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *PostgresUsers) ContactTaken(ctx context.Context, email, phone string, exceptID uint) (string, error) {
+	var count int64
+	if email != "" {
+		if err := r.db.WithContext(ctx).Model(&model.User{}).
+			Where("LOWER(email) = LOWER(?) AND id <> ?", email, exceptID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return "email", nil
+		}
+	}
+	if phone != "" {
+		if err := r.db.WithContext(ctx).Model(&model.User{}).
+			Where("phone = ? AND id <> ?", phone, exceptID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return "phone", nil
+		}
+	}
+	return "", nil
+}
+
+func (r *PostgresUsers) Save(ctx context.Context, user *model.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *PostgresUsers) SetKurirStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).
+		Where("id = ? AND role = ?", id, "kurir").
+		Update("status", status).Error
+}
+
+// PostgresOrders menyimpan pesanan di tabel orders
+type PostgresOrders struct {
+	db *gorm.DB
+}
+
+func NewPostgresOrders(db *gorm.DB) *PostgresOrders {
+	return &PostgresOrders{db: db}
+}
+
+func (r *PostgresOrders) Create(ctx context.Context, order *model.Order) error {
+	// Relasi tidak ikut di-insert; Customer/Kurir hanya dirujuk lewat ID
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(order).Error
+}
+
+func (r *PostgresOrders) FindByID(ctx context.Context, id uint) (model.Order, error) {
+	var order model.Order
+	err := r.db.WithContext(ctx).Preload("Customer").Preload("Kurir").First(&order, id).Error
+	return order, notFound(err)
+}
+
+func (r *PostgresOrders) Save(ctx context.Context, order *model.Order) error {
+	// Omit relasi supaya data user tidak ikut tersimpan ulang
+	return r.db.WithContext(ctx).Omit("Customer", "Kurir").Save(order).Error
+}
+
+func (r *PostgresOrders) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.Order{}, id).Error
+}
+
+func (r *PostgresOrders) List(ctx context.Context, q OrderListQuery) ([]model.Order, int64, error) {
+	direction := " ASC"
+	if q.Desc {
+		direction = " DESC"
+	}
+
+	// Session supaya query bisa dipakai ulang untuk count dan find
+	query := q.Filter.Apply(JoinOrderUsers(r.db.WithContext(ctx).Model(&model.Order{}))).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []model.Order
+	err := query.
+		Select("orders.*").
+		Preload("Customer").
+		Preload("Kurir").
+		Order(OrderSortColumns[q.Sort] + direction + ", orders.id" + direction).
+		Limit(q.Limit).
+		Offset((q.Page - 1) * q.Limit).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+func (r *PostgresOrders) UpdateStatus(ctx context.Context, id uint, status string) error {
+	// Pakai struct agar UpdatedAt ikut berubah otomatis
+	return r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("id = ?", id).
+		Updates(model.Order{Status: status}).Error
+}
+
+func (r *PostgresOrders) UpdatePaymentStatus(ctx context.Context, id uint, status string) error {
+	return r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("id = ?", id).
+		Update("payment_status", status).Error
+}
+
+func (r *PostgresOrders) ListByCustomer(ctx context.Context, customerID uint) ([]model.Order, error) {
+	var orders []model.Order
+	err := r.db.WithContext(ctx).
+		Preload("Kurir").
+		Where("customer_id = ?", customerID).
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *PostgresOrders) ListByKurir(ctx context.Context, q KurirOrderQuery) ([]model.Order, error) {
+	query := r.db.WithContext(ctx).Preload("Customer").Where("kurir_id = ?", q.KurirID)
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+	if q.From != nil {
+		query = query.Where("updated_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		query = query.Where("updated_at < ?", *q.To)
+	}
+
+	var orders []model.Order
+	err := query.Find(&orders).Error
+	return orders, err
+}
+
+func (r *PostgresOrders) CountActiveByKurir(ctx context.Context, kurirID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("kurir_id = ? AND status = ?", kurirID, "proses").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *PostgresOrders) HasActiveDelivery(ctx context.Context, customerID, kurirID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("customer_id = ? AND kurir_id = ? AND status = ?", customerID, kurirID, "proses").
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *PostgresOrders) Revenue(ctx context.Context, q RevenueQuery) (float64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("status = ? AND updated_at >= ? AND updated_at < ?", "selesai", q.From, q.To)
+	if q.KurirID != 0 {
+		query = query.Where("kurir_id = ?", q.KurirID)
+	}
+
+	// COALESCE supaya hasilnya 0 kalau tidak ada data
+	var total float64
+	err := query.Select("COALESCE(SUM(nominal), 0)").Scan(&total).Error
+	return total, err
+}
+
+func (r *PostgresOrders) CountCompleted(ctx context.Context, from, to time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.Order{}).
+		Where("status = ? AND updated_at >= ? AND updated_at < ?", "selesai", from, to).
+		Count(&count).Error
+	return count, err
+}
+
+// PostgresMessages menyimpan chat di tabel messages
+type PostgresMessages struct {
+	db *gorm.DB
+}
+
+func NewPostgresMessages(db *gorm.DB) *PostgresMessages {
+	return &PostgresMessages{db: db}
+}
+
+func (r *PostgresMessages) Create(ctx context.Context, msg *model.Message) error {
+	return r.db.WithContext(ctx).Omit("Sender").Create(msg).Error
+}
+
+func (r *PostgresMessages) ListByOrder(ctx context.Context, orderID uint) ([]model.Message, error) {
+	var messages []model.Message
+	err := r.db.WithContext(ctx).
+		Preload("Sender").
+		Where("order_id = ?", orderID).
+		Order("sent_at ASC").
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *PostgresMessages) DeleteByOrder(ctx context.Context, orderID uint) error {
+	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&model.Message{}).Error
+}
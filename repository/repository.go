@@ -0,0 +1,114 @@
+// Package repository memisahkan akses data dari handler. Setiap repository
+// punya implementasi Postgres (gorm) dan implementasi memori untuk test.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"gorm.io/gorm"
+)
+
+// ErrNotFound dikembalikan kalau data yang dicari tidak ada
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+type UserRepository interface {
+	FindByID(ctx context.Context, id uint) (model.User, error)
+	FindKurir(ctx context.Context, id uint) (model.User, error)
+	// FindByLogin user dengan email (tanpa beda huruf besar/kecil) atau nomor HP ini
+	FindByLogin(ctx context.Context, email, phone string) (model.User, error)
+	List(ctx context.Context) ([]model.User, error)
+	// AvailableKurir: kurir online dan aktif
+	AvailableKurir(ctx context.Context) ([]model.User, error)
+	// ContactTaken mengembalikan "email" / "phone" kalau sudah dipakai user lain
+	ContactTaken(ctx context.Context, email, phone string, exceptID uint) (string, error)
+	// Save membuat user baru kalau ID masih 0
+	Save(ctx context.Context, user *model.User) error
+	SetKurirStatus(ctx context.Context, id uint, status string) error
+}
+
+// KurirOrderQuery memfilter pesanan milik satu kurir. Status kosong berarti
+// semua status; From/To (eksklusif) memfilter updated_at.
+type KurirOrderQuery struct {
+	KurirID uint
+	Status  string
+	From    *time.Time
+	To      *time.Time
+}
+
+// RevenueQuery menjumlahkan nominal pesanan selesai pada [From, To).
+// KurirID 0 berarti semua kurir.
+type RevenueQuery struct {
+	KurirID uint
+	From    time.Time
+	To      time.Time
+}
+
+type OrderRepository interface {
+	Create(ctx context.Context, order *model.Order) error
+	// FindByID memuat pesanan beserta Customer dan Kurir
+	FindByID(ctx context.Context, id uint) (model.Order, error)
+	Save(ctx context.Context, order *model.Order) error
+	Delete(ctx context.Context, id uint) error
+	// List satu halaman pesanan beserta Customer dan Kurir, dan total seluruh hasil filter
+	List(ctx context.Context, q OrderListQuery) ([]model.Order, int64, error)
+	UpdateStatus(ctx context.Context, id uint, status string) error
+	UpdatePaymentStatus(ctx context.Context, id uint, status string) error
+	ListByCustomer(ctx context.Context, customerID uint) ([]model.Order, error)
+	ListByKurir(ctx context.Context, q KurirOrderQuery) ([]model.Order, error)
+	CountActiveByKurir(ctx context.Context, kurirID uint) (int64, error)
+	// HasActiveDelivery: ada pesanan customer yang sedang diantar kurir ini
+	HasActiveDelivery(ctx context.Context, customerID, kurirID uint) (bool, error)
+	Revenue(ctx context.Context, q RevenueQuery) (float64, error)
+	CountCompleted(ctx context.Context, from, to time.Time) (int64, error)
+}
+
+type MessageRepository interface {
+	Create(ctx context.Context, msg *model.Message) error
+	// ListByOrder urut sent_at naik, Sender ikut dimuat
+	ListByOrder(ctx context.Context, orderID uint) ([]model.Message, error)
+	DeleteByOrder(ctx context.Context, orderID uint) error
+}
+
+// Location posisi terakhir seorang kurir
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+type LocationRepository interface {
+	Set(ctx context.Context, kurirID uint, loc Location) error
+	Get(ctx context.Context, kurirID uint) (Location, error)
+}
+
+// Repositories dipakai untuk merakit service di main maupun di test
+type Repositories struct {
+	Users     UserRepository
+	Orders    OrderRepository
+	Messages  MessageRepository
+	Locations LocationRepository
+}
+
+// NewPostgres memakai database untuk user, order dan pesan. Lokasi kurir
+// memang hanya disimpan di memori proses.
+func NewPostgres(db *gorm.DB) Repositories {
+	return Repositories{
+		Users:     NewPostgresUsers(db),
+		Orders:    NewPostgresOrders(db),
+		Messages:  NewPostgresMessages(db),
+		Locations: NewMemoryLocations(),
+	}
+}
+
+// NewMemory seluruhnya di memori, untuk test tanpa Postgres
+func NewMemory() Repositories {
+	users := NewMemoryUsers()
+	return Repositories{
+		Users:     users,
+		Orders:    NewMemoryOrders(users),
+		Messages:  NewMemoryMessages(users),
+		Locations: NewMemoryLocations(),
+	}
+}
@@ -1,81 +1,163 @@
 package route
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/apperror"
 	"github.com/mubarok-ridho/misi-paket.backend/controller"
 	handlers "github.com/mubarok-ridho/misi-paket.backend/handler"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
 	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/permission"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+)
+
+// Batas request per kelompok route (token bucket)
+var (
+	authLimit  = ratelimit.Policy{Name: "auth", Limit: 10, Period: time.Minute, Burst: 5}
+	chatLimit  = ratelimit.Policy{Name: "chat", Limit: 30, Period: time.Minute, Burst: 10}
+	trackLimit = ratelimit.Policy{Name: "track", Limit: 60, Period: time.Minute, Burst: 10}
+	apiLimit   = ratelimit.Policy{Name: "api", Limit: 300, Period: time.Minute, Burst: 60}
 )
 
-func SetupRoutes(r *gin.Engine) {
+// SetupRoutes memasang semua endpoint. Handler yang butuh data dibangun
+// dari svc sehingga route bisa dirakit dengan repository palsu saat test.
+func SetupRoutes(r *gin.Engine, svc service.Services) {
+	orders := controller.NewOrderHandler(svc.Orders)
+	users := controller.NewUserHandler(svc.Users)
+	locations := controller.NewLocationHandler(svc.Locations)
+	chat := handlers.NewChatHandler(svc.Chat)
+	centrifugo := handlers.NewCentrifugoHandler(svc.Users)
+	accounts := controller.NewAuthHandler(svc.Users)
+
+	// Semua error dijawab dalam satu bentuk berisi code dan request_id,
+	// pesan mengikuti Accept-Language (id atau en)
+	r.Use(middleware.RequestID(), middleware.Language(), middleware.ErrorHandler())
+	r.NoRoute(func(c *gin.Context) {
+		c.Error(apperror.RouteNotFound)
+	})
+
 	// ✅ Root
 	r.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{"message": "FaiExpress API is running!"})
+		c.JSON(200, gin.H{"message": i18n.Msg(c, "msg.api_running")})
 	})
 
-	// ✅ WebSocket Chat (per Order ID)
-	r.POST("/chat/send", handlers.SendChatMessage)
-	r.GET("/centrifugo/token", handlers.GenerateCentrifugoToken)
-	r.GET("/chat/load/:order_id", handlers.GetMessagesByOrderID)
-	r.GET("/orders/:id/status", controller.CheckOrderKurirReady)
+	// ✅ WebSocket Chat (per Order ID) — path lama dipertahankan untuk
+	// aplikasi mobile, tapi wajib login
+	jwt := middleware.JWTAuthMiddleware()
+	authRL := ratelimit.Middleware(authLimit, ratelimit.ByIP)
+	chatRL := ratelimit.Middleware(chatLimit, ratelimit.ByUser)
+	r.POST("/chat/send", jwt, chatRL, chat.SendChatMessage)
+	r.GET("/centrifugo/token", jwt, handlers.GenerateCentrifugoToken)
+	r.GET("/centrifugo/subscription-token", jwt, handlers.GenerateSubscriptionToken)
+	r.POST("/centrifugo/subscribe", centrifugo.SubscribeProxy) // dipanggil server Centrifugo
+	r.GET("/chat/load/:order_id", jwt, chat.GetMessagesByOrderID)
+	r.GET("/orders/:id/status", jwt, orders.CheckOrderKurirReady)
 
-	r.GET("/kaithheathcheck", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "OK"})
-	})
+	// ✅ Health check (liveness & readiness)
+	r.GET("/healthz", controller.Healthz)
+	r.GET("/readyz", controller.Readyz)
 
 	// ✅ Auth
-	r.POST("/register", controller.Register)
-	r.POST("/login", controller.Login)
-	r.PUT("/users/:id/password", controller.ChangePassword)
+	r.POST("/register", authRL, accounts.Register)
+	r.POST("/login", authRL, accounts.Login)
+	r.POST("/auth/refresh", authRL, controller.RefreshToken)
+	r.POST("/auth/otp/request", authRL, controller.RequestLoginOTP)
+	r.POST("/auth/otp/login", authRL, controller.LoginWithOTP)
+	r.POST("/auth/forgot-password", authRL, controller.ForgotPassword)
+	r.POST("/auth/reset-password", authRL, controller.ResetPassword)
+	r.POST("/kurir/apply", authRL, accounts.ApplyKurir)
 
 	// ✅ Tracking
-	r.POST("/kurir/track", controller.UpdateKurirLocation)
-	r.GET("/kurir/track/:id", controller.GetKurirLocation)
-	r.GET("/kurir/:id/location", controller.GetKurirLocation)
-	r.GET("/kurir/available", controller.GetAvailableKurir)
-	r.PUT("/api/orders/tagihan", middleware.AuthMiddleware(), controller.UpdateTagihan)
-	r.PUT("/api/orders/payment-validasi", middleware.AuthMiddleware(), controller.ValidasiPembayaran)
-	r.PUT("/api/orders/:id/metode_bayar", middleware.AuthMiddleware(), controller.UpdatePaymentMethod)
-	r.GET("/pendapatan/total-today", controller.GetTotalPendapatanToday)
-	r.DELETE("/messages/order/:id", controller.DeleteMessagesByOrderID)
+	r.POST("/kurir/track", jwt, ratelimit.Middleware(trackLimit, ratelimit.ByUser), middleware.RequirePermission(permission.KurirTrack), locations.UpdateKurirLocation)
+	r.GET("/kurir/track/:id", jwt, locations.GetKurirLocation)
+	r.GET("/kurir/:id/location", jwt, locations.GetKurirLocation)
+	r.GET("/kurir/available", jwt, users.GetAvailableKurir)
+	r.PUT("/api/orders/tagihan", jwt, middleware.RequirePermission(permission.PaymentsBill), orders.UpdateTagihan)
+	r.PUT("/api/orders/payment-validasi", jwt, middleware.RequirePermission(permission.PaymentsValidate), orders.ValidasiPembayaran)
+	r.PUT("/api/orders/:id/metode_bayar", jwt, orders.UpdatePaymentMethod)
+	r.GET("/pendapatan/total-today", jwt, middleware.RequirePermission(permission.ReportsView), orders.GetTotalPendapatanToday)
+	r.DELETE("/messages/order/:id", jwt, chat.DeleteMessagesByOrderID)
 
 	// ✅ Protected with JWT
 	auth := r.Group("/api")
-	auth.Use(middleware.JWTAuthMiddleware())
+	auth.Use(middleware.JWTAuthMiddleware(), ratelimit.Middleware(apiLimit, ratelimit.ByUser))
+
+	// Session
+	auth.POST("/logout", controller.Logout)
+	auth.POST("/logout-all", controller.LogoutAll)
+	auth.PUT("/password", controller.ChangePassword)
+	auth.GET("/sessions", controller.GetMySessions)
+	auth.DELETE("/sessions/:id", controller.RevokeMySession)
+
+	// Verifikasi nomor HP
+	auth.POST("/phone/verify/request", controller.RequestPhoneVerification)
+	auth.POST("/phone/verify", controller.VerifyPhone)
 
 	// Kurir
-	auth.GET("/kurir/:id/orders", middleware.RoleMiddleware("kurir"), controller.GetOrdersForKurir)
-	auth.PUT("/kurir/status", middleware.RoleMiddleware("admin"), controller.UpdateKurirStatus)
-	auth.PUT("/kurir/location", middleware.RoleMiddleware("kurir"), controller.UpdateLocation)
-	auth.GET("/kurir/:id", controller.GetKurirByID)
-	auth.PUT("/kurir/up/:id", middleware.RoleMiddleware("kurir"), controller.UpdateKurirByID)
-	auth.GET("/kurir/:id/orders/proses", controller.GetOrdersProses)
-	auth.GET("/kurir/:id/orders/selesai/today", controller.GetOrdersSelesaiToday)
-	auth.GET("/pendapatan/kurir/:id/today", controller.GetPendapatanKurirToday)
+	auth.POST("/kurir", middleware.RequirePermission(permission.KurirManage), users.CreateKurir)
+	auth.GET("/kurir/applications", middleware.RequirePermission(permission.KurirManage), controller.GetKurirApplications)
+	auth.GET("/kurir/applications/:id", middleware.RequirePermission(permission.KurirManage), controller.GetKurirApplication)
+	auth.PUT("/kurir/applications/:id/approve", middleware.RequirePermission(permission.KurirManage), controller.ApproveKurirApplication)
+	auth.PUT("/kurir/applications/:id/reject", middleware.RequirePermission(permission.KurirManage), controller.RejectKurirApplication)
+	auth.GET("/kurir/documents/expiring", middleware.RequirePermission(permission.KurirManage), controller.GetExpiringKurirDocuments)
+	auth.GET("/kurir/documents/:id/file", middleware.RequirePermission(permission.KurirManage), controller.GetKurirDocumentFile)
+	auth.GET("/kurir/:id/orders", middleware.RequirePermission(permission.OrdersReadAssigned), orders.GetOrdersForKurir)
+	auth.PUT("/kurir/status", middleware.RequirePermission(permission.KurirDispatch), users.UpdateKurirStatus)
+	auth.PUT("/kurir/location", ratelimit.Middleware(trackLimit, ratelimit.ByUser), middleware.RequirePermission(permission.KurirTrack), controller.UpdateLocation)
+	auth.GET("/kurir/:id", users.GetKurirByID)
+	auth.PUT("/kurir/up/:id", middleware.RequirePermission(permission.KurirProfile), users.UpdateKurirByID)
+	auth.GET("/kurir/:id/orders/proses", orders.GetOrdersProses)
+	auth.GET("/kurir/:id/orders/selesai/today", orders.GetOrdersSelesaiToday)
+	auth.GET("/pendapatan/kurir/:id/today", orders.GetPendapatanKurirToday)
 
 	// Customer - Orders
-	auth.POST("/orders", middleware.RoleMiddleware("customer"), controller.CreateOrder)
-	auth.GET("/my-orders", middleware.RoleMiddleware("customer"), controller.GetMyOrders)
-	auth.PUT("/update-profile", controller.UpdateProfile) // ⬅️ Ini baru
+	auth.POST("/orders", middleware.RequirePermission(permission.OrdersCreate), orders.CreateOrder)
+	auth.GET("/my-orders", middleware.RequirePermission(permission.OrdersReadOwn), orders.GetMyOrders)
+	auth.PUT("/update-profile", users.UpdateProfile) // ⬅️ Ini baru
 
 	// Admin - Orders
-	auth.GET("/orders", middleware.RoleMiddleware("admin"), controller.GetAllOrders)
-	auth.GET("/orders/:id", controller.GetOrderByID)
-	auth.PUT("/orders/:id", controller.UpdateOrder)
-	auth.DELETE("/orders/:id", middleware.RoleMiddleware("admin"), controller.DeleteOrder)
-	auth.PUT("/orders/status", middleware.RoleMiddleware("kurir"), controller.UpdateOrderStatus)
-	auth.GET("/orders/total-selesai-today", controller.GetTotalOrdersSelesaiToday)
-	auth.GET("/pendapatan/total-all-today", controller.GetAllTotalPendapatanToday)
+	auth.GET("/orders", middleware.RequirePermission(permission.OrdersReadAll), orders.GetAllOrders)
+	auth.GET("/orders/:id", orders.GetOrderByID)
+	auth.PUT("/orders/:id", orders.UpdateOrder)
+	auth.DELETE("/orders/:id", middleware.RequirePermission(permission.OrdersDelete), orders.DeleteOrder)
+	auth.PUT("/orders/status", middleware.RequirePermission(permission.OrdersUpdateStatus), orders.UpdateOrderStatus)
+	auth.GET("/orders/total-selesai-today", middleware.RequirePermission(permission.ReportsView), orders.GetTotalOrdersSelesaiToday)
+	auth.GET("/pendapatan/total-all-today", middleware.RequirePermission(permission.ReportsView), orders.GetAllTotalPendapatanToday)
+
+	// Admin - Export (csv / xlsx)
+	auth.GET("/export/orders", middleware.RequirePermission(permission.ReportsView), controller.ExportOrders)
+	auth.GET("/export/payments", middleware.RequirePermission(permission.ReportsView), controller.ExportPayments)
+	auth.GET("/export/kurir-performance", middleware.RequirePermission(permission.ReportsView), controller.ExportKurirPerformance)
 
 	// Chat via REST API (opsional)
-	auth.POST("/chat", middleware.RoleMiddleware("customer", "kurir"), controller.SendChat)
-	auth.GET("/chat", middleware.RoleMiddleware("customer", "kurir"), controller.GetChat)
+	auth.POST("/chat", chatRL, middleware.RequirePermission(permission.ChatUse), controller.SendChat)
+	auth.GET("/chat", middleware.RequirePermission(permission.ChatUse), controller.GetChat)
 
 	// Admin - User CRUD
-	auth.GET("/users", middleware.RoleMiddleware("admin"), controller.GetAllUsers)
-	auth.GET("/users/:id", middleware.RoleMiddleware("admin"), controller.GetUserByID)
-	auth.PUT("/users/:id", middleware.RoleMiddleware("admin"), controller.UpdateUser)
-	auth.DELETE("/users/:id", middleware.RoleMiddleware("admin"), controller.SoftDeleteUser)
-	auth.GET("/users/profile", middleware.RoleMiddleware("customer"), controller.GetUserProfile)
+	auth.GET("/users", middleware.RequirePermission(permission.UsersManage), users.GetAllUsers)
+	auth.GET("/users/:id", middleware.RequirePermission(permission.UsersManage), users.GetUserByID)
+	auth.PUT("/users/:id", middleware.RequirePermission(permission.UsersManage), users.UpdateUser)
+	auth.DELETE("/users/:id", middleware.RequirePermission(permission.UsersManage), users.SoftDeleteUser)
+	auth.POST("/users/:id/reset-password", middleware.RequirePermission(permission.UsersManage), controller.AdminResetPassword)
+	auth.DELETE("/users/:id/login-lock", middleware.RequirePermission(permission.UsersManage), controller.UnlockUserLogin)
+	auth.GET("/login-locks", middleware.RequirePermission(permission.UsersManage), controller.GetLockedLogins)
+	auth.DELETE("/login-locks/:id", middleware.RequirePermission(permission.UsersManage), controller.UnlockLogin)
+	auth.GET("/users/profile", users.GetUserProfile)
+
+	// Admin - Sesi device user (mis. kurir kehilangan HP)
+	auth.GET("/users/:id/sessions", middleware.RequirePermission(permission.SessionsManage), controller.GetUserSessions)
+	auth.DELETE("/users/:id/sessions", middleware.RequirePermission(permission.SessionsManage), controller.RevokeAllUserSessions)
+	auth.DELETE("/users/:id/sessions/:session_id", middleware.RequirePermission(permission.SessionsManage), controller.RevokeUserSession)
+
+	// Admin - Role & permission
+	auth.GET("/permissions", middleware.RequirePermission(permission.RolesManage), controller.GetPermissionCatalog)
+	auth.GET("/roles", middleware.RequirePermission(permission.RolesManage), controller.GetRoles)
+	auth.POST("/roles", middleware.RequirePermission(permission.RolesManage), controller.CreateRole)
+	auth.PUT("/roles/:name/permissions", middleware.RequirePermission(permission.RolesManage), controller.UpdateRolePermissions)
+	auth.DELETE("/roles/:name", middleware.RequirePermission(permission.RolesManage), controller.DeleteRole)
+	auth.PUT("/users/:id/role", middleware.RequirePermission(permission.RolesManage), controller.AssignUserRole)
 
 }
@@ -0,0 +1,306 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mubarok-ridho/misi-paket.backend/auth"
+	"github.com/mubarok-ridho/misi-paket.backend/middleware"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/ratelimit"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/service"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// Hasil yang diharapkan per role. passed berarti lolos autentikasi dan
+// otorisasi (bukan 401/403); dipakai untuk handler yang masih butuh database.
+const (
+	passed       = 0
+	unauthorized = http.StatusUnauthorized
+	forbidden    = http.StatusForbidden
+	ok           = http.StatusOK
+	created      = http.StatusCreated
+)
+
+// Data seed: ID mengikuti urutan pembuatan di repository memori
+const (
+	customerID    = 1
+	kurirID       = 2
+	adminID       = 3
+	spareUserID   = 4 // dinonaktifkan lewat DELETE /api/users/:id
+	orderID       = 1 // milik customer, diantar kurir
+	spareOrderID  = 2 // dihapus lewat DELETE /api/orders/:id
+	testJWTSecret = "route-test-secret-0123456789abcdef"
+)
+
+type routeCase struct {
+	method string
+	route  string // path seperti didaftarkan di gin
+	id     string // nilai :id kalau bukan orderID
+	body   interface{}
+
+	anonymous, customer, kurir, admin int
+}
+
+var routeMatrix = []routeCase{
+	{method: "GET", route: "/", anonymous: ok, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/healthz", anonymous: ok, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/readyz", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+
+	// Auth publik
+	{method: "POST", route: "/register", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/login", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/auth/refresh", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/auth/otp/request", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/auth/otp/login", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/auth/forgot-password", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/auth/reset-password", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/kurir/apply", anonymous: passed, customer: passed, kurir: passed, admin: passed},
+
+	// Centrifugo; subscribe proxy hanya untuk server Centrifugo (header rahasia)
+	{method: "GET", route: "/centrifugo/token", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "GET", route: "/centrifugo/subscription-token", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/centrifugo/subscribe", body: map[string]string{"user": "1", "channel": "chat:1"},
+		anonymous: unauthorized, customer: unauthorized, kurir: unauthorized, admin: unauthorized},
+
+	// Chat & status pesanan di luar /api
+	{method: "POST", route: "/chat/send", body: map[string]string{"order_id": "1", "message": "halo"},
+		anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/chat/load/:order_id", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "DELETE", route: "/messages/order/:id", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/orders/:id/status", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+
+	// Tracking & tagihan
+	{method: "POST", route: "/kurir/track", body: map[string]float64{"lat": -6.2, "lng": 106.8},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/kurir/track/:id", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/kurir/:id/location", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/kurir/available", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/orders/tagihan", body: map[string]int{"id": orderID, "nominal": 15000},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/orders/payment-validasi", body: map[string]int{"id": orderID},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/orders/:id/metode_bayar", body: map[string]string{"metode_bayar": "cash"},
+		anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/pendapatan/total-today", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+
+	// Sesi & verifikasi nomor HP
+	{method: "POST", route: "/api/logout", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/api/logout-all", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "PUT", route: "/api/password", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "GET", route: "/api/sessions", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "DELETE", route: "/api/sessions/:id", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/api/phone/verify/request", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "POST", route: "/api/phone/verify", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+
+	// Kurir
+	{method: "POST", route: "/api/kurir", body: map[string]string{
+		"name": "Kurir Baru", "email": "kurir.baru@example.com", "phone": "081298765432",
+		"password": "Rahasia123", "kendaraan": "Motor", "plat_nomor": "B 1234 XY",
+	}, anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: created},
+	{method: "GET", route: "/api/kurir/applications", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/kurir/applications/:id", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "PUT", route: "/api/kurir/applications/:id/approve", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "PUT", route: "/api/kurir/applications/:id/reject", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/kurir/documents/expiring", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/kurir/documents/:id/file", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/kurir/:id/orders", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/kurir/status", body: map[string]interface{}{"id": kurirID, "status": "offline"},
+		anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "PUT", route: "/api/kurir/location", body: map[string]float64{"lat": -6.2, "lng": 106.8},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/kurir/:id", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/kurir/up/:id", id: fmt.Sprint(kurirID), body: map[string]string{"name": "Kurir"},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/kurir/:id/orders/proses", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/kurir/:id/orders/selesai/today", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/pendapatan/kurir/:id/today", id: fmt.Sprint(kurirID), anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+
+	// Pesanan
+	{method: "POST", route: "/api/orders", body: map[string]interface{}{"kurir_id": kurirID, "layanan": "antar barang"},
+		anonymous: unauthorized, customer: created, kurir: forbidden, admin: created},
+	{method: "GET", route: "/api/my-orders", anonymous: unauthorized, customer: ok, kurir: forbidden, admin: ok},
+	{method: "PUT", route: "/api/update-profile", body: map[string]string{"name": "Nama Baru"},
+		anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/orders", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "GET", route: "/api/orders/:id", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "PUT", route: "/api/orders/:id", body: map[string]string{"status": "proses"},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "DELETE", route: "/api/orders/:id", id: fmt.Sprint(spareOrderID), anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "PUT", route: "/api/orders/status", body: map[string]interface{}{"id": orderID, "status": "proses"},
+		anonymous: unauthorized, customer: forbidden, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/orders/total-selesai-today", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "GET", route: "/api/pendapatan/total-all-today", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+
+	// Export
+	{method: "GET", route: "/api/export/orders", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/export/payments", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/export/kurir-performance", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+
+	// Chat REST lama
+	{method: "POST", route: "/api/chat", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+	{method: "GET", route: "/api/chat", anonymous: unauthorized, customer: passed, kurir: passed, admin: passed},
+
+	// User
+	{method: "GET", route: "/api/users", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "GET", route: "/api/users/profile", anonymous: unauthorized, customer: ok, kurir: ok, admin: ok},
+	{method: "GET", route: "/api/users/:id", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "PUT", route: "/api/users/:id", body: map[string]string{"name": "Customer"},
+		anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "DELETE", route: "/api/users/:id", id: fmt.Sprint(spareUserID), anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "POST", route: "/api/users/:id/reset-password", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "DELETE", route: "/api/users/:id/login-lock", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/login-locks", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "DELETE", route: "/api/login-locks/:id", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "GET", route: "/api/users/:id/sessions", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "DELETE", route: "/api/users/:id/sessions", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "DELETE", route: "/api/users/:id/sessions/:session_id", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+
+	// Role & permission
+	{method: "GET", route: "/api/permissions", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: ok},
+	{method: "GET", route: "/api/roles", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "POST", route: "/api/roles", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "PUT", route: "/api/roles/:name/permissions", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "DELETE", route: "/api/roles/:name", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+	{method: "PUT", route: "/api/users/:id/role", anonymous: unauthorized, customer: forbidden, kurir: forbidden, admin: passed},
+}
+
+// allowAll store rate limit yang tidak pernah menolak, supaya matrix tidak
+// bergantung pada jumlah request per menit
+type allowAll struct{}
+
+func (allowAll) Take(context.Context, string, ratelimit.Policy, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+// newTestRouter merakit route dengan repository memori. Session dianggap
+// valid selama token-nya sah, jadi tabel sessions tidak dibutuhkan.
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if err := utils.LoadKeys(utils.KeyConfig{Algorithm: "HS256", KeyID: "test", Secret: testJWTSecret}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	middleware.SetSessionValidator(func(*utils.JWTClaims, string) error { return nil })
+	ratelimit.SetStore(allowAll{})
+	t.Cleanup(func() {
+		middleware.SetSessionValidator(auth.ValidateSession)
+		ratelimit.SetStore(ratelimit.NewMemoryStore())
+	})
+
+	ctx := context.Background()
+	repos := repository.NewMemory()
+	for _, u := range []model.User{
+		{Name: "Customer", Email: "customer@example.com", Phone: "081200000001", Role: "customer", StatusKerja: "aktif"},
+		{Name: "Kurir", Email: "kurir@example.com", Phone: "081200000002", Role: "kurir", Status: "online", StatusKerja: "aktif"},
+		{Name: "Admin", Email: "admin@example.com", Phone: "081200000003", Role: "admin", StatusKerja: "aktif"},
+		{Name: "Cadangan", Email: "cadangan@example.com", Phone: "081200000004", Role: "customer", StatusKerja: "aktif"},
+	} {
+		if err := repos.Users.Save(ctx, &u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		order := model.Order{CustomerID: customerID, KurirID: kurirID, Layanan: "antar barang", Status: "proses"}
+		if err := repos.Orders.Create(ctx, &order); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repos.Locations.Set(ctx, kurirID, repository.Location{Lat: -6.2, Lng: 106.8}); err != nil {
+		t.Fatal(err)
+	}
+
+	pub := service.PublisherFunc(func(context.Context, string, interface{}) error { return nil })
+	r := gin.New()
+	SetupRoutes(r, service.New(repos, pub, func(uint, uint) error { return nil }))
+	return r
+}
+
+func bearer(t *testing.T, userID uint, role string) string {
+	t.Helper()
+	token, err := utils.GenerateToken(userID, role, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func (rc routeCase) path() string {
+	id := rc.id
+	if id == "" {
+		id = fmt.Sprint(orderID)
+	}
+	return strings.NewReplacer(
+		":order_id", fmt.Sprint(orderID),
+		":session_id", "1",
+		":name", "customer",
+		":id", id,
+	).Replace(rc.route)
+}
+
+func TestRouteAuthorizationMatrix(t *testing.T) {
+	r := newTestRouter(t)
+
+	// Setiap route yang terdaftar wajib punya baris di matrix
+	covered := map[string]bool{}
+	for _, rc := range routeMatrix {
+		covered[rc.method+" "+rc.route] = true
+	}
+	for _, info := range r.Routes() {
+		if !covered[info.Method+" "+info.Path] {
+			t.Errorf("route %s %s belum ada di routeMatrix", info.Method, info.Path)
+		}
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	roles := []struct {
+		name  string
+		token string
+		want  func(routeCase) int
+	}{
+		{"anonymous", "", func(rc routeCase) int { return rc.anonymous }},
+		{"customer", bearer(t, customerID, "customer"), func(rc routeCase) int { return rc.customer }},
+		{"kurir", bearer(t, kurirID, "kurir"), func(rc routeCase) int { return rc.kurir }},
+		{"admin", bearer(t, adminID, "admin"), func(rc routeCase) int { return rc.admin }},
+	}
+
+	for _, rc := range routeMatrix {
+		t.Run(rc.method+" "+rc.route, func(t *testing.T) {
+			for _, role := range roles {
+				var body bytes.Buffer
+				if rc.body != nil {
+					json.NewEncoder(&body).Encode(rc.body)
+				} else {
+					body.WriteString("{}")
+				}
+				req := httptest.NewRequest(rc.method, rc.path(), &body)
+				req.Header.Set("Content-Type", "application/json")
+				if role.token != "" {
+					req.Header.Set("Authorization", role.token)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				want := role.want(rc)
+				switch {
+				case want == passed && (w.Code == unauthorized || w.Code == forbidden):
+					t.Errorf("%s: status %d, want neither 401 nor 403: %s", role.name, w.Code, w.Body)
+				case want != passed && w.Code != want:
+					t.Errorf("%s: status %d, want %d: %s", role.name, w.Code, want, w.Body)
+				}
+			}
+		})
+	}
+}
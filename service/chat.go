@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/centrifugo"
+	"github.com/mubarok-ridho/misi-paket.backend/dto"
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+type ChatService struct {
+	orders    repository.OrderRepository
+	messages  repository.MessageRepository
+	publisher Publisher
+	now       func() time.Time
+}
+
+func NewChatService(orders repository.OrderRepository, messages repository.MessageRepository, pub Publisher) *ChatService {
+	return &ChatService{orders: orders, messages: messages, publisher: pub, now: time.Now}
+}
+
+// order memastikan actor adalah peserta chat pesanan ini
+func (s *ChatService) order(ctx context.Context, a policy.Actor, orderID uint, allow func(policy.Actor, model.Order) bool) (model.Order, error) {
+	order, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return order, orderErr(err)
+	}
+	if !allow(a, order) {
+		return order, ErrForbidden
+	}
+	return order, nil
+}
+
+// Send menyimpan pesan lalu mem-publish ke channel chat pesanan. Penerima
+// ditentukan dari pesanan, bukan dari input client. Pesan tetap tersimpan
+// walaupun publish gagal; error publish dibungkus ErrPublish.
+func (s *ChatService) Send(ctx context.Context, a policy.Actor, orderID uint, content string) (model.Message, error) {
+	order, err := s.order(ctx, a, orderID, policy.Chat)
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	receiverID := order.CustomerID
+	if a.ID == order.CustomerID {
+		receiverID = order.KurirID
+	}
+
+	msg := model.Message{
+		OrderID:    order.ID,
+		SenderID:   a.ID,
+		ReceiverID: receiverID,
+		Content:    content,
+		SentAt:     s.now(),
+	}
+	if err := s.messages.Create(ctx, &msg); err != nil {
+		return msg, err
+	}
+
+	if err := s.publisher.Publish(ctx, centrifugo.ChatChannel(order.ID), dto.NewMessageView(msg)); err != nil {
+		return msg, fmt.Errorf("%w: %w", ErrPublish, err)
+	}
+	return msg, nil
+}
+
+func (s *ChatService) History(ctx context.Context, a policy.Actor, orderID uint) ([]model.Message, error) {
+	if _, err := s.order(ctx, a, orderID, policy.Chat); err != nil {
+		return nil, err
+	}
+	return s.messages.ListByOrder(ctx, orderID)
+}
+
+func (s *ChatService) Clear(ctx context.Context, a policy.Actor, orderID uint) error {
+	if _, err := s.order(ctx, a, orderID, policy.ClearChat); err != nil {
+		return err
+	}
+	return s.messages.DeleteByOrder(ctx, orderID)
+}
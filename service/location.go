@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+type LocationService struct {
+	locations repository.LocationRepository
+	orders    repository.OrderRepository
+}
+
+func NewLocationService(locations repository.LocationRepository, orders repository.OrderRepository) *LocationService {
+	return &LocationService{locations: locations, orders: orders}
+}
+
+// Track mencatat posisi terakhir kurir
+func (s *LocationService) Track(ctx context.Context, kurirID uint, loc repository.Location) error {
+	return s.locations.Set(ctx, kurirID, loc)
+}
+
+// Locate: kurir itu sendiri, pengelola, atau customer yang pesanannya
+// sedang diantar kurir tersebut
+func (s *LocationService) Locate(ctx context.Context, a policy.Actor, kurirID uint) (repository.Location, error) {
+	if !policy.ViewKurirOrders(a, kurirID) {
+		active, err := s.orders.HasActiveDelivery(ctx, a.ID, kurirID)
+		if err != nil {
+			return repository.Location{}, err
+		}
+		if !active {
+			return repository.Location{}, ErrForbidden
+		}
+	}
+
+	loc, err := s.locations.Get(ctx, kurirID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return loc, ErrLocationNotFound
+	}
+	return loc, err
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+type OrderService struct {
+	orders repository.OrderRepository
+	users  repository.UserRepository
+	now    func() time.Time
+}
+
+func NewOrderService(orders repository.OrderRepository, users repository.UserRepository) *OrderService {
+	return &OrderService{orders: orders, users: users, now: time.Now}
+}
+
+// Create menyimpan pesanan baru. Status selalu "proses" dan tagihan
+// belum ada; keduanya diubah lewat endpoint masing-masing.
+func (s *OrderService) Create(ctx context.Context, order *model.Order) error {
+	if order.KurirID == 0 {
+		return ErrKurirRequired
+	}
+	order.Status = "proses"
+	order.Nominal = nil
+	order.PaymentStatus = nil
+	return s.orders.Create(ctx, order)
+}
+
+// Authorize memuat pesanan (beserta Customer & Kurir) lalu mengecek policy-nya
+func (s *OrderService) Authorize(ctx context.Context, a policy.Actor, id uint, allow func(policy.Actor, model.Order) bool) (model.Order, error) {
+	order, err := s.orders.FindByID(ctx, id)
+	if err != nil {
+		return order, orderErr(err)
+	}
+	if !allow(a, order) {
+		return order, ErrForbidden
+	}
+	return order, nil
+}
+
+// ActiveOrders jumlah pesanan "proses" yang sedang dipegang kurir
+func (s *OrderService) ActiveOrders(ctx context.Context, kurirID uint) (int64, error) {
+	return s.orders.CountActiveByKurir(ctx, kurirID)
+}
+
+// Save menyimpan perubahan pesanan. Kurir kembali online begitu
+// pesanannya selesai.
+func (s *OrderService) Save(ctx context.Context, order *model.Order) error {
+	if err := s.orders.Save(ctx, order); err != nil {
+		return err
+	}
+	return s.afterStatusChange(ctx, *order)
+}
+
+// List daftar pesanan untuk admin (filter, urutan dan halaman)
+func (s *OrderService) List(ctx context.Context, q repository.OrderListQuery) ([]model.Order, int64, error) {
+	return s.orders.List(ctx, q)
+}
+
+func (s *OrderService) Delete(ctx context.Context, id uint) error {
+	return s.orders.Delete(ctx, id)
+}
+
+func (s *OrderService) ChoosePaymentMethod(ctx context.Context, a policy.Actor, id uint, method string) error {
+	order, err := s.Authorize(ctx, a, id, policy.ChoosePaymentMethod)
+	if err != nil {
+		return err
+	}
+	order.MetodeBayar = method
+	return s.orders.Save(ctx, &order)
+}
+
+func (s *OrderService) UpdateStatus(ctx context.Context, a policy.Actor, id uint, status string) error {
+	order, err := s.Authorize(ctx, a, id, policy.UpdateOrderStatus)
+	if err != nil {
+		return err
+	}
+	if err := s.orders.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	order.Status = status
+	return s.afterStatusChange(ctx, order)
+}
+
+// Bill mengisi tagihan; status pembayaran kembali "pending"
+func (s *OrderService) Bill(ctx context.Context, a policy.Actor, id uint, nominal uint) error {
+	order, err := s.Authorize(ctx, a, id, policy.BillOrder)
+	if err != nil {
+		return err
+	}
+	status := "pending"
+	order.Nominal = &nominal
+	order.PaymentStatus = &status
+	return s.orders.Save(ctx, &order)
+}
+
+func (s *OrderService) ValidatePayment(ctx context.Context, a policy.Actor, id uint) error {
+	if _, err := s.Authorize(ctx, a, id, policy.ValidatePayment); err != nil {
+		return err
+	}
+	return s.orders.UpdatePaymentStatus(ctx, id, "done")
+}
+
+func (s *OrderService) ListByCustomer(ctx context.Context, customerID uint) ([]model.Order, error) {
+	return s.orders.ListByCustomer(ctx, customerID)
+}
+
+// ListByKurir dengan status kosong berarti semua pesanan kurir
+func (s *OrderService) ListByKurir(ctx context.Context, kurirID uint, status string) ([]model.Order, error) {
+	return s.orders.ListByKurir(ctx, repository.KurirOrderQuery{KurirID: kurirID, Status: status})
+}
+
+// CompletedTodayByKurir pesanan kurir yang selesai hari ini (WIB)
+func (s *OrderService) CompletedTodayByKurir(ctx context.Context, kurirID uint) ([]model.Order, error) {
+	from, to := today(s.now())
+	return s.orders.ListByKurir(ctx, repository.KurirOrderQuery{
+		KurirID: kurirID,
+		Status:  "selesai",
+		From:    &from,
+		To:      &to,
+	})
+}
+
+// RevenueToday total nominal pesanan selesai hari ini; kurirID 0 untuk semua kurir
+func (s *OrderService) RevenueToday(ctx context.Context, kurirID uint) (float64, error) {
+	from, to := today(s.now())
+	return s.orders.Revenue(ctx, repository.RevenueQuery{KurirID: kurirID, From: from, To: to})
+}
+
+func (s *OrderService) CompletedToday(ctx context.Context) (int64, error) {
+	from, to := today(s.now())
+	return s.orders.CountCompleted(ctx, from, to)
+}
+
+// afterStatusChange: kurir kembali online setelah pesanannya selesai, kecuali
+// kurir yang sudah tidak aktif (aturan yang sama dengan UserService.SetKurirStatus)
+func (s *OrderService) afterStatusChange(ctx context.Context, order model.Order) error {
+	if order.Status != "selesai" || order.KurirID == 0 {
+		return nil
+	}
+	kurir, err := s.users.FindKurir(ctx, order.KurirID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if kurir.StatusKerja != "aktif" {
+		return nil
+	}
+	return s.users.SetKurirStatus(ctx, order.KurirID, "online")
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/policy"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+// Pesanan selesai hanya membuat kurir aktif kembali online
+func TestCompletedOrderSetsOnlyActiveKurirOnline(t *testing.T) {
+	cases := []struct {
+		statusKerja string
+		want        string
+	}{
+		{"aktif", "online"},
+		{"nonaktif", "offline"},
+		{"menunggu", "offline"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.statusKerja, func(t *testing.T) {
+			ctx := context.Background()
+			repos := repository.NewMemory()
+			orders := NewOrderService(repos.Orders, repos.Users)
+
+			kurir := model.User{Name: "kurir", Role: "kurir", Status: "offline", StatusKerja: tc.statusKerja}
+			if err := repos.Users.Save(ctx, &kurir); err != nil {
+				t.Fatal(err)
+			}
+			order := model.Order{CustomerID: 99, KurirID: kurir.ID, Layanan: "antar barang"}
+			if err := orders.Create(ctx, &order); err != nil {
+				t.Fatal(err)
+			}
+
+			admin := policy.Actor{ID: 1, Role: "admin"}
+			if err := orders.UpdateStatus(ctx, admin, order.ID, "selesai"); err != nil {
+				t.Fatalf("UpdateStatus: %v", err)
+			}
+			got, err := repos.Users.FindByID(ctx, kurir.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tc.want {
+				t.Fatalf("kurir status %q, want %q", got.Status, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package service berisi aturan bisnis. Service hanya bergantung pada
+// interface repository sehingga bisa dijalankan dengan fake di memori.
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+)
+
+var (
+	ErrOrderNotFound    = errors.New("pesanan tidak ditemukan")
+	ErrUserNotFound     = errors.New("user tidak ditemukan")
+	ErrKurirNotFound    = errors.New("kurir tidak ditemukan")
+	ErrLocationNotFound = errors.New("lokasi belum tersedia")
+	ErrForbidden        = errors.New("akses ditolak")
+	ErrKurirRequired    = errors.New("kurir tidak boleh kosong")
+	ErrKurirInactive    = errors.New("kurir belum disetujui atau tidak aktif")
+	ErrPublish          = errors.New("gagal mengirim pesan realtime")
+)
+
+// ContactTakenError: email atau nomor HP sudah dipakai user lain
+type ContactTakenError struct {
+	Field string // "email" atau "phone"
+}
+
+func (e *ContactTakenError) Error() string {
+	if e.Field == "phone" {
+		return "Nomor HP sudah terdaftar"
+	}
+	return "Email sudah terdaftar"
+}
+
+// Publisher mengirim data realtime ke sebuah channel (Centrifugo)
+type Publisher interface {
+	Publish(ctx context.Context, channel string, data interface{}) error
+}
+
+// PublisherFunc supaya fungsi biasa seperti centrifugo.Publish bisa dipakai
+type PublisherFunc func(ctx context.Context, channel string, data interface{}) error
+
+func (f PublisherFunc) Publish(ctx context.Context, channel string, data interface{}) error {
+	return f(ctx, channel, data)
+}
+
+// SessionRevoker mencabut semua sesi user kecuali exceptSessionID
+type SessionRevoker func(userID uint, exceptSessionID uint) error
+
+// Services dirakit sekali di main (atau harness test) lalu dipakai route
+type Services struct {
+	Orders    *OrderService
+	Users     *UserService
+	Chat      *ChatService
+	Locations *LocationService
+}
+
+func New(repos repository.Repositories, pub Publisher, revoke SessionRevoker) Services {
+	return Services{
+		Orders:    NewOrderService(repos.Orders, repos.Users),
+		Users:     NewUserService(repos.Users, repos.Orders, revoke),
+		Chat:      NewChatService(repos.Orders, repos.Messages, pub),
+		Locations: NewLocationService(repos.Locations, repos.Orders),
+	}
+}
+
+// today mengembalikan awal hari ini dan besok (WIB)
+func today(now time.Time) (time.Time, time.Time) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		loc = time.FixedZone("WIB", 7*60*60)
+	}
+	now = now.In(loc)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// orderErr menerjemahkan ErrNotFound repository
+func orderErr(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrOrderNotFound
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mubarok-ridho/misi-paket.backend/model"
+	"github.com/mubarok-ridho/misi-paket.backend/repository"
+	"github.com/mubarok-ridho/misi-paket.backend/utils"
+)
+
+// Kurir dengan pesanan aktif sebanyak ini tidak ditawarkan lagi
+const maxActiveOrders = 5
+
+type UserService struct {
+	users          repository.UserRepository
+	orders         repository.OrderRepository
+	revokeSessions SessionRevoker
+}
+
+func NewUserService(users repository.UserRepository, orders repository.OrderRepository, revoke SessionRevoker) *UserService {
+	return &UserService{users: users, orders: orders, revokeSessions: revoke}
+}
+
+// AvailableKurir kurir online yang masih bisa menerima pesanan
+type AvailableKurir struct {
+	User         model.User
+	ActiveOrders int64
+}
+
+// Profile field yang boleh diubah user sendiri atau pengelola kurir; field
+// nil tidak diubah. Kendaraan dan PlatNomor hanya dipakai untuk profil kurir.
+type Profile struct {
+	Name      *string
+	Phone     *string
+	Email     *string
+	Kendaraan *string
+	PlatNomor *string
+}
+
+func (s *UserService) List(ctx context.Context) ([]model.User, error) {
+	return s.users.List(ctx)
+}
+
+func (s *UserService) Get(ctx context.Context, id uint) (model.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return user, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (s *UserService) Kurir(ctx context.Context, id uint) (model.User, error) {
+	user, err := s.users.FindKurir(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return user, ErrKurirNotFound
+	}
+	return user, err
+}
+
+// FindForLogin user dengan email atau nomor HP identifier
+func (s *UserService) FindForLogin(ctx context.Context, identifier string) (model.User, error) {
+	user, err := s.users.FindByLogin(ctx, identifier, utils.NormalizePhone(identifier))
+	if errors.Is(err, repository.ErrNotFound) {
+		return user, ErrUserNotFound
+	}
+	return user, err
+}
+
+// CheckNewUser kebijakan password (*utils.PasswordError) dan keunikan
+// email / nomor HP (*ContactTakenError) untuk user yang akan dibuat
+func (s *UserService) CheckNewUser(ctx context.Context, user model.User, password string) error {
+	if err := utils.ValidatePassword(password, user.Name, user.Email); err != nil {
+		return err
+	}
+	return s.checkContact(ctx, user.Email, user.Phone, 0)
+}
+
+// Create memeriksa user baru lalu menyimpannya dengan password ter-hash
+func (s *UserService) Create(ctx context.Context, user *model.User, password string) error {
+	if err := s.CheckNewUser(ctx, *user, password); err != nil {
+		return err
+	}
+
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	return s.users.Save(ctx, user)
+}
+
+func (s *UserService) AvailableKurir(ctx context.Context) ([]AvailableKurir, error) {
+	kurirs, err := s.users.AvailableKurir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var available []AvailableKurir
+	for _, kurir := range kurirs {
+		count, err := s.orders.CountActiveByKurir(ctx, kurir.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count < maxActiveOrders {
+			available = append(available, AvailableKurir{User: kurir, ActiveOrders: count})
+		}
+	}
+	return available, nil
+}
+
+// UpdateProfile data kontak user (oleh dirinya sendiri atau admin);
+// nomor baru harus diverifikasi ulang
+func (s *UserService) UpdateProfile(ctx context.Context, id uint, p Profile) error {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.applyProfile(ctx, &user, p)
+}
+
+func (s *UserService) UpdateKurirProfile(ctx context.Context, id uint, p Profile) error {
+	user, err := s.Kurir(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.applyProfile(ctx, &user, p)
+}
+
+func (s *UserService) applyProfile(ctx context.Context, user *model.User, p Profile) error {
+	if err := s.checkContact(ctx, deref(p.Email), deref(p.Phone), user.ID); err != nil {
+		return err
+	}
+
+	if p.Name != nil {
+		user.Name = *p.Name
+	}
+	if p.Phone != nil && *p.Phone != user.Phone {
+		user.Phone = *p.Phone
+		user.PhoneVerifiedAt = nil
+	}
+	if p.Email != nil {
+		user.Email = *p.Email
+	}
+	if p.Kendaraan != nil {
+		user.Kendaraan = p.Kendaraan
+	}
+	if p.PlatNomor != nil {
+		user.PlatNomor = p.PlatNomor
+	}
+	return s.users.Save(ctx, user)
+}
+
+// SetKurirStatus: hanya kurir yang sudah disetujui (aktif) yang boleh online
+func (s *UserService) SetKurirStatus(ctx context.Context, id uint, status string) error {
+	if status == "online" {
+		kurir, err := s.Kurir(ctx, id)
+		if err != nil {
+			return err
+		}
+		if kurir.StatusKerja != "aktif" {
+			return ErrKurirInactive
+		}
+	}
+	return s.users.SetKurirStatus(ctx, id, status)
+}
+
+// Deactivate menonaktifkan user dan langsung mencabut semua sesinya
+func (s *UserService) Deactivate(ctx context.Context, id uint) error {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	user.StatusKerja = "nonaktif"
+	if err := s.users.Save(ctx, &user); err != nil {
+		return err
+	}
+	return s.revokeSessions(user.ID, 0)
+}
+
+func (s *UserService) checkContact(ctx context.Context, email, phone string, exceptID uint) error {
+	field, err := s.users.ContactTaken(ctx, email, phone, exceptID)
+	if err != nil {
+		return err
+	}
+	if field != "" {
+		return &ContactTakenError{Field: field}
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
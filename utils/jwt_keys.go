@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Panjang minimum secret HS256 (256 bit)
+const minSecretLength = 32
+
+// Secret yang pernah ada di repo; siapa pun bisa memalsukan token dengannya,
+// jadi hanya boleh dipakai di APP_ENV=development
+var knownDevSecrets = []string{
+	"rahasiafai-dev-jwt-secret-ganti-di-production",
+}
+
+// SigningKey adalah satu kunci JWT yang dikenali lewat kid
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   interface{} // nil untuk kunci lama yang hanya dipakai verifikasi
+	verify interface{}
+}
+
+// KeySet berisi satu kunci aktif untuk menandatangani dan beberapa kunci
+// lama yang masih diterima selama masa rotasi
+type KeySet struct {
+	Active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+var jwtKeys = struct {
+	sync.RWMutex
+	set *KeySet
+}{}
+
+var ErrNoSigningKey = errors.New("kunci JWT belum dimuat")
+
+// KeyConfig adalah pengaturan kunci JWT:
+//
+//	Algorithm      HS256 (default), RS256 atau EdDSA
+//	KeyID          kid kunci aktif
+//	Secret         secret HS256
+//	PrivateKeyFile file PEM untuk RS256/EdDSA
+//	OldSecrets     "kid=secret,..." kunci HS256 lama yang masih diterima
+//	OldPublicKeys  "kid=/path/pub.pem,..." kunci publik lama yang masih diterima
+type KeyConfig struct {
+	Algorithm      string `yaml:"alg"`
+	KeyID          string `yaml:"kid"`
+	Secret         string `yaml:"secret"`
+	PrivateKeyFile string `yaml:"private_key_file"`
+	OldSecrets     string `yaml:"old_secrets"`
+	OldPublicKeys  string `yaml:"old_public_keys"`
+}
+
+// LoadKeys membangun KeySet dan memasangnya. Dipanggil sekali saat start;
+// error berarti server tidak boleh jalan.
+func LoadKeys(cfg KeyConfig, env string) error {
+	if err := CheckDevSecret(cfg, env); err != nil {
+		return err
+	}
+	set, err := NewKeySet(cfg)
+	if err != nil {
+		return err
+	}
+
+	jwtKeys.Lock()
+	jwtKeys.set = set
+	jwtKeys.Unlock()
+	return nil
+}
+
+// NewKeySet memvalidasi konfigurasi dan memuat semua kunci
+func NewKeySet(cfg KeyConfig) (*KeySet, error) {
+	if cfg.KeyID == "" {
+		cfg.KeyID = "default"
+	}
+
+	active := &SigningKey{ID: cfg.KeyID}
+	switch strings.ToUpper(cfg.Algorithm) {
+	case "", "HS256":
+		secret, err := checkSecret("JWT_SECRET", cfg.Secret)
+		if err != nil {
+			return nil, err
+		}
+		active.Method, active.sign, active.verify = jwt.SigningMethodHS256, secret, secret
+	case "RS256":
+		pem, err := readKeyFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE bukan kunci RSA: %w", err)
+		}
+		active.Method, active.sign, active.verify = jwt.SigningMethodRS256, key, &key.PublicKey
+	case "EDDSA":
+		pem, err := readKeyFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE bukan kunci Ed25519: %w", err)
+		}
+		priv := key.(ed25519.PrivateKey)
+		active.Method, active.sign, active.verify = jwt.SigningMethodEdDSA, priv, priv.Public()
+	default:
+		return nil, fmt.Errorf("JWT_ALG %q tidak didukung (HS256, RS256, EdDSA)", cfg.Algorithm)
+	}
+
+	set := &KeySet{Active: active, keys: map[string]*SigningKey{active.ID: active}}
+
+	for kid, secret := range parseKeyList(cfg.OldSecrets) {
+		b, err := checkSecret("JWT_OLD_SECRETS "+kid, secret)
+		if err != nil {
+			return nil, err
+		}
+		if err := set.add(&SigningKey{ID: kid, Method: jwt.SigningMethodHS256, verify: b}); err != nil {
+			return nil, err
+		}
+	}
+
+	for kid, path := range parseKeyList(cfg.OldPublicKeys) {
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_OLD_PUBLIC_KEYS %s: %w", kid, err)
+		}
+		key.ID = kid
+		if err := set.add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+func (s *KeySet) add(k *SigningKey) error {
+	if _, exists := s.keys[k.ID]; exists {
+		return fmt.Errorf("kid JWT %q dipakai lebih dari sekali", k.ID)
+	}
+	s.keys[k.ID] = k
+	return nil
+}
+
+// lookup mencari kunci verifikasi untuk token. Token lama tanpa kid
+// diverifikasi dengan kunci aktif.
+func (s *KeySet) lookup(t *jwt.Token) (interface{}, error) {
+	key := s.Active
+	if kid, ok := t.Header["kid"].(string); ok && kid != "" {
+		if key, ok = s.keys[kid]; !ok {
+			return nil, errors.New("kid token tidak dikenal")
+		}
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("metode signing tidak dikenali")
+	}
+	return key.verify, nil
+}
+
+func currentKeys() (*KeySet, error) {
+	jwtKeys.RLock()
+	defer jwtKeys.RUnlock()
+	if jwtKeys.set == nil {
+		return nil, ErrNoSigningKey
+	}
+	return jwtKeys.set, nil
+}
+
+// CheckDevSecret menolak secret development di luar APP_ENV=development
+func CheckDevSecret(cfg KeyConfig, env string) error {
+	if env == "development" {
+		return nil
+	}
+	secrets := []string{cfg.Secret}
+	for _, secret := range parseKeyList(cfg.OldSecrets) {
+		secrets = append(secrets, secret)
+	}
+	for _, secret := range secrets {
+		for _, dev := range knownDevSecrets {
+			if secret == dev {
+				return fmt.Errorf("JWT_SECRET development tidak boleh dipakai di APP_ENV=%s", env)
+			}
+		}
+	}
+	return nil
+}
+
+func checkSecret(name, secret string) ([]byte, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("%s wajib diisi", name)
+	}
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("%s minimal %d karakter", name, minSecretLength)
+	}
+	return []byte(secret), nil
+}
+
+func readKeyFile(path string) ([]byte, error) {
+	if path == "" {
+		return nil, errors.New("JWT_PRIVATE_KEY_FILE wajib diisi untuk RS256/EdDSA")
+	}
+	return os.ReadFile(path)
+}
+
+func loadPublicKey(path string) (*SigningKey, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return &SigningKey{Method: jwt.SigningMethodRS256, verify: key}, nil
+	}
+	if key, err := jwt.ParseEdPublicKeyFromPEM(pem); err == nil {
+		return &SigningKey{Method: jwt.SigningMethodEdDSA, verify: key}, nil
+	}
+	return nil, errors.New("bukan kunci publik RSA atau Ed25519")
+}
+
+// parseKeyList membaca format "kid=value,kid2=value2"
+func parseKeyList(raw string) map[string]string {
+	out := map[string]string{}
+	for _, item := range strings.Split(raw, ",") {
+		kid, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if ok && kid != "" {
+			out[kid] = value
+		}
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordError pelanggaran kebijakan password. Key menunjuk pesan di
+// katalog i18n supaya controller bisa menampilkannya dalam bahasa request.
+type PasswordError struct {
+	Key    string
+	Params map[string]string
+}
+
+func (e *PasswordError) Error() string {
+	return i18n.T(i18n.Default, e.Key, e.Params)
+}
+
+var ErrWeakPassword = &PasswordError{Key: "password.weak"}
+
+// Password yang terlalu umum selalu ditolak walau lolos aturan lain
+var commonPasswords = map[string]bool{
+	"password1": true, "password123": true, "12345678a": true, "qwerty123": true,
+	"abc12345": true, "admin123": true, "bismillah1": true, "faiexpress1": true,
+	"iloveyou1": true, "indonesia1": true, "passw0rd": true, "a1234567": true,
+}
+
+// Batas panjang bcrypt, byte setelahnya diabaikan diam-diam
+const maxPasswordBytes = 72
+
+var passwordPolicy = struct {
+	minLength  int
+	bcryptCost int
+}{8, bcrypt.DefaultCost}
+
+// SetPasswordPolicy dipanggil saat startup dengan nilai dari konfigurasi.
+// Nilai di luar rentang aman diabaikan.
+func SetPasswordPolicy(minLength, bcryptCost int) {
+	if minLength >= 8 && minLength <= maxPasswordBytes {
+		passwordPolicy.minLength = minLength
+	}
+	if bcryptCost >= 10 && bcryptCost <= 14 {
+		passwordPolicy.bcryptCost = bcryptCost
+	}
+}
+
+// PasswordMinLength panjang minimal password (default 8)
+func PasswordMinLength() int {
+	return passwordPolicy.minLength
+}
+
+// ValidatePassword menerapkan kebijakan password: panjang minimal,
+// ada huruf dan angka, bukan password umum, dan tidak memuat identitas user
+// (nama depan / bagian depan email) kalau diberikan.
+func ValidatePassword(password string, identities ...string) error {
+	minLength := PasswordMinLength()
+	if len([]rune(password)) < minLength {
+		return &PasswordError{Key: "password.too_short", Params: map[string]string{"min": strconv.Itoa(minLength)}}
+	}
+	if len(password) > maxPasswordBytes {
+		return &PasswordError{Key: "password.too_long", Params: map[string]string{"max": strconv.Itoa(maxPasswordBytes)}}
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return ErrWeakPassword
+	}
+
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] {
+		return &PasswordError{Key: "password.common"}
+	}
+	for _, id := range identities {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if at := strings.Index(id, "@"); at >= 0 {
+			id = id[:at]
+		}
+		if fields := strings.Fields(id); len(fields) > 0 {
+			id = fields[0]
+		}
+		if len(id) >= 4 && strings.Contains(lower, id) {
+			return &PasswordError{Key: "password.personal"}
+		}
+	}
+	return nil
+}
+
+// BcryptCost cost bcrypt yang sedang dipakai (default 10)
+func BcryptCost() int {
+	return passwordPolicy.bcryptCost
+}
+
+// HashPassword membuat hash bcrypt dengan cost dari konfigurasi
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), BcryptCost())
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
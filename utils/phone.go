@@ -0,0 +1,16 @@
+package utils
+
+import "strings"
+
+// NormalizePhone menyeragamkan nomor HP Indonesia ke format 08xxx
+// ("+62 812-3456" dan "62812..." jadi "0812...")
+func NormalizePhone(phone string) string {
+	p := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(strings.TrimSpace(phone))
+	switch {
+	case strings.HasPrefix(p, "+62"):
+		p = "0" + p[3:]
+	case strings.HasPrefix(p, "62"):
+		p = "0" + p[2:]
+	}
+	return p
+}
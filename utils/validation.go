@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/mubarok-ridho/misi-paket.backend/i18n"
+)
+
+// Nomor HP Indonesia setelah NormalizePhone: 08 + 8..12 digit
+var phoneIDPattern = regexp.MustCompile(`^08[0-9]{8,12}$`)
+
+func init() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	// Pakai nama field JSON di pesan error, bukan nama field Go
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return f.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("phone_id", func(fl validator.FieldLevel) bool {
+		return phoneIDPattern.MatchString(NormalizePhone(fl.Field().String()))
+	})
+}
+
+// ValidationErrors menerjemahkan error binding ke pesan per field dalam
+// bahasa lang. Error selain validasi (mis. JSON rusak) dikembalikan di key "_".
+func ValidationErrors(lang string, err error) map[string]string {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return map[string]string{"_": i18n.T(lang, "validation.malformed", nil)}
+	}
+
+	out := make(map[string]string, len(verrs))
+	for _, fe := range verrs {
+		out[fe.Field()] = validationMessage(lang, fe)
+	}
+	return out
+}
+
+// ValidationMessage mengembalikan satu pesan (field pertama) untuk key "error"
+func ValidationMessage(lang string, err error) string {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) && len(verrs) > 0 {
+		return validationMessage(lang, verrs[0])
+	}
+	return i18n.T(lang, "validation.malformed", nil)
+}
+
+func validationMessage(lang string, fe validator.FieldError) string {
+	params := map[string]string{"field": fe.Field(), "param": fe.Param()}
+	key := "validation.invalid"
+	switch fe.Tag() {
+	case "required", "email", "phone_id":
+		key = "validation." + fe.Tag()
+	case "min", "max":
+		key = "validation." + fe.Tag()
+		if fe.Kind() == reflect.String {
+			key += "_len"
+		}
+	case "oneof":
+		key = "validation.oneof"
+		params["param"] = strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return i18n.T(lang, key, params)
+}